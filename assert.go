@@ -11,7 +11,14 @@
 
 package berry
 
-import "reflect"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 //
 // Check if value is a primitve number or not. Returns
@@ -70,3 +77,346 @@ func IsMap(value interface{}) bool {
 
 	return false
 }
+
+//
+// Returned by `Unflatten` when two flattened keys cannot
+// both be represented in the nested structure, such as `a`
+// and `a.b`.
+//
+var ErrKeyConflict = errors.New("Conflicting keys")
+
+//
+// Defines how slice indexes are written in flattened keys.
+//
+type FlattenIndexStyle int
+
+const (
+	FlattenIndexDot     FlattenIndexStyle = iota // indexes are written as `hosts.0`
+	FlattenIndexBracket                          // indexes are written as `hosts[0]`
+)
+
+//
+// Options that control how `Flatten` and `Unflatten` build
+// and parse keys. A `nil` options value uses the defaults.
+//
+type FlattenOptions struct {
+	Separator  string            // separator between keys, defaults to `.`
+	IndexStyle FlattenIndexStyle // how slice indexes are written
+	MaxDepth   int               // levels to flatten, values deeper are kept as is; `0` means no limit
+}
+
+// return the separator to use
+func (options *FlattenOptions) separator() string {
+	if options == nil || options.Separator == "" {
+		return "."
+	}
+
+	return options.Separator
+}
+
+//
+// Flatten the nested maps and slices into a single level map
+// with keys like `db.hosts.0.name`. Nested values may be any map
+// or slice, map keys are converted to strings. Empty maps and
+// slices are retained as values so that they survive a round
+// trip via `Unflatten`. Returns `nil` if data is `nil`.
+//
+func Flatten(data map[string]interface{}, options *FlattenOptions) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	if options == nil {
+		options = &FlattenOptions{}
+	}
+
+	result := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		flattenValue(result, key, value, 1, options)
+	}
+
+	return result
+}
+
+// recursively flatten the value into the result
+func flattenValue(result map[string]interface{}, prefix string, value interface{}, depth int, options *FlattenOptions) {
+	if options.MaxDepth > 0 && depth >= options.MaxDepth {
+		result[prefix] = value
+		return
+	}
+
+	if IsMap(value) {
+		reflected := reflect.ValueOf(value)
+		if reflected.Len() == 0 {
+			result[prefix] = value
+			return
+		}
+
+		separator := options.separator()
+		iterator := reflected.MapRange()
+		for iterator.Next() {
+			key := fmt.Sprint(iterator.Key().Interface())
+			flattenValue(result, prefix+separator+key, iterator.Value().Interface(), depth+1, options)
+		}
+		return
+	}
+
+	if IsSlice(value) {
+		reflected := reflect.ValueOf(value)
+		if reflected.Len() == 0 {
+			result[prefix] = value
+			return
+		}
+
+		for index := 0; index < reflected.Len(); index++ {
+			var key string
+			if options.IndexStyle == FlattenIndexBracket {
+				key = prefix + "[" + strconv.Itoa(index) + "]"
+			} else {
+				key = prefix + options.separator() + strconv.Itoa(index)
+			}
+
+			flattenValue(result, key, reflected.Index(index).Interface(), depth+1, options)
+		}
+		return
+	}
+
+	result[prefix] = value
+}
+
+//
+// Unflatten a single level map with keys like `db.hosts.0.name`
+// back into nested `map[string]interface{}` and `[]interface{}`
+// values. With `FlattenIndexDot` every numeric segment is treated
+// as a slice index. Returns an error wrapping `ErrKeyConflict` if
+// two keys collide, for example `a` and `a.b`, or `a.0` and `a.b`,
+// and an error if the indexes of a slice leave a gap, for example
+// `a.0` and `a.5` without the indexes in between. Returns `nil` if
+// the map is `nil`.
+//
+func Unflatten(flat map[string]interface{}, options *FlattenOptions) (map[string]interface{}, error) {
+	if flat == nil {
+		return nil, nil
+	}
+
+	if options == nil {
+		options = &FlattenOptions{}
+	}
+
+	// sort keys so that errors are reported deterministically
+	keys := MapKeysSorted(flat)
+	parsed := make([][]pathSegment, len(keys))
+	for index, key := range keys {
+		segments, err := parseFlattenedKey(key, options)
+		if err != nil {
+			return nil, err
+		}
+		parsed[index] = segments
+	}
+
+	// place slice elements in the order of their indexes, so that
+	// each one extends the slice by at most one element
+	order := make([]int, len(keys))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return compareSegments(parsed[order[i]], parsed[order[j]]) < 0
+	})
+
+	result := make(map[string]interface{})
+	for _, index := range order {
+		_, err := unflattenValue(result, parsed[index], flattenedLeaf{flat[keys[index]]}, keys[index])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	unwrapLeaves(result)
+	return result, nil
+}
+
+// wraps the values of the flattened map while unflattening, so
+// that they are never used as containers for other keys, even
+// if they are maps, slices or `nil`
+type flattenedLeaf struct {
+	value interface{}
+}
+
+// replace the wrapped values in the containers built by
+// `Unflatten` with the values themselves
+func unwrapLeaves(value interface{}) interface{} {
+	switch v := value.(type) {
+	case flattenedLeaf:
+		return v.value
+
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = unwrapLeaves(child)
+		}
+
+	case []interface{}:
+		for index, child := range v {
+			v[index] = unwrapLeaves(child)
+		}
+	}
+
+	return value
+}
+
+// split a flattened key into its path segments
+func parseFlattenedKey(key string, options *FlattenOptions) ([]pathSegment, error) {
+	parts := strings.Split(key, options.separator())
+	segments := make([]pathSegment, 0, len(parts))
+
+	for index, part := range parts {
+		if options.IndexStyle == FlattenIndexDot {
+			if part == "" {
+				return nil, fmt.Errorf("Empty segment in key: %s", key)
+			}
+
+			number, err := strconv.Atoi(part)
+			if index > 0 && err == nil && number >= 0 {
+				segments = append(segments, pathSegment{index: number, isIndex: true})
+			} else {
+				segments = append(segments, pathSegment{key: part})
+			}
+			continue
+		}
+
+		// bracket style, a part looks like `hosts[0][1]`
+		name := part
+		brackets := ""
+		if start := strings.IndexByte(part, '['); start >= 0 {
+			name = part[:start]
+			brackets = part[start:]
+		}
+
+		if name == "" && (index == 0 || brackets == "") {
+			return nil, fmt.Errorf("Empty segment in key: %s", key)
+		}
+
+		if name != "" {
+			segments = append(segments, pathSegment{key: name})
+		}
+
+		for brackets != "" {
+			end := strings.IndexByte(brackets, ']')
+			if brackets[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("Invalid index in key: %s", key)
+			}
+
+			number, err := strconv.Atoi(brackets[1:end])
+			if err != nil || number < 0 {
+				return nil, fmt.Errorf("Invalid index in key: %s", key)
+			}
+
+			segments = append(segments, pathSegment{index: number, isIndex: true})
+			brackets = brackets[end+1:]
+		}
+	}
+
+	return segments, nil
+}
+
+// compare two paths segment by segment, with indexes compared as
+// numbers and before keys
+func compareSegments(a []pathSegment, b []pathSegment) int {
+	for index := 0; index < len(a) && index < len(b); index++ {
+		first, second := a[index], b[index]
+		switch {
+		case first.isIndex && second.isIndex:
+			if first.index != second.index {
+				return first.index - second.index
+			}
+
+		case first.isIndex != second.isIndex:
+			if first.isIndex {
+				return -1
+			}
+			return 1
+
+		case first.key != second.key:
+			return strings.Compare(first.key, second.key)
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// recursively place the value in the container and return the
+// updated container. Containers are only ever created here, the
+// value is a `flattenedLeaf` that conflicts with any other key.
+func unflattenValue(container interface{}, segments []pathSegment, value interface{}, key string) (interface{}, error) {
+	segment := segments[0]
+	last := len(segments) == 1
+
+	if segment.isIndex {
+		var list []interface{}
+		switch c := container.(type) {
+		case nil:
+			list = nil
+
+		case []interface{}:
+			list = c
+
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+		}
+
+		if segment.index > len(list) {
+			return nil, fmt.Errorf("Missing index before key: %s", key)
+		}
+
+		if segment.index == len(list) {
+			list = append(list, nil)
+		}
+
+		if last {
+			if list[segment.index] != nil {
+				return nil, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+			}
+
+			list[segment.index] = value
+			return list, nil
+		}
+
+		child, err := unflattenValue(list[segment.index], segments[1:], value, key)
+		if err != nil {
+			return nil, err
+		}
+
+		list[segment.index] = child
+		return list, nil
+	}
+
+	var m map[string]interface{}
+	switch c := container.(type) {
+	case nil:
+		m = make(map[string]interface{})
+
+	case map[string]interface{}:
+		m = c
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+	}
+
+	existing, found := m[segment.key]
+	if last {
+		if found {
+			return nil, fmt.Errorf("%w: %s", ErrKeyConflict, key)
+		}
+
+		m[segment.key] = value
+		return m, nil
+	}
+
+	child, err := unflattenValue(existing, segments[1:], value, key)
+	if err != nil {
+		return nil, err
+	}
+
+	m[segment.key] = child
+	return m, nil
+}
@@ -12,6 +12,8 @@
 package berry
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -117,3 +119,132 @@ func TestIsMap(t *testing.T) {
 	assert.False(t, IsMap(make([]float64, 1)))
 	assert.False(t, IsMap(make([]string, 1)))
 }
+
+func getFlattenTestData() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "app",
+		"db": map[string]interface{}{
+			"hosts": []interface{}{
+				map[string]interface{}{"name": "primary", "port": 5432},
+				map[string]interface{}{"name": "replica", "port": 5433},
+			},
+			"options": map[string]interface{}{},
+		},
+		"tags": []interface{}{},
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	assert.Nil(t, Flatten(nil, nil))
+
+	expected := map[string]interface{}{
+		"name":            "app",
+		"db.hosts.0.name": "primary",
+		"db.hosts.0.port": 5432,
+		"db.hosts.1.name": "replica",
+		"db.hosts.1.port": 5433,
+		"db.options":      map[string]interface{}{},
+		"tags":            []interface{}{},
+	}
+	assert.Equal(t, expected, Flatten(getFlattenTestData(), nil))
+
+	// bracket style with custom separator
+	expected = map[string]interface{}{
+		"name":             "app",
+		"db_hosts[0]_name": "primary",
+		"db_hosts[0]_port": 5432,
+		"db_hosts[1]_name": "replica",
+		"db_hosts[1]_port": 5433,
+		"db_options":       map[string]interface{}{},
+		"tags":             []interface{}{},
+	}
+	assert.Equal(t, expected, Flatten(getFlattenTestData(), &FlattenOptions{Separator: "_", IndexStyle: FlattenIndexBracket}))
+
+	// max depth
+	flat := Flatten(getFlattenTestData(), &FlattenOptions{MaxDepth: 2})
+	assert.Equal(t, 4, len(flat))
+	assert.Equal(t, 2, len(flat["db.hosts"].([]interface{})))
+
+	// typed nested values
+	flat = Flatten(map[string]interface{}{"ports": map[string]int{"http": 80}, "ids": []string{"x"}}, nil)
+	assert.Equal(t, map[string]interface{}{"ports.http": 80, "ids.0": "x"}, flat)
+}
+
+func TestUnflatten(t *testing.T) {
+	nested, err := Unflatten(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, nested)
+
+	// round trip
+	data := getFlattenTestData()
+	nested, err = Unflatten(Flatten(data, nil), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, data, nested)
+
+	options := &FlattenOptions{Separator: "/", IndexStyle: FlattenIndexBracket}
+	nested, err = Unflatten(Flatten(data, options), options)
+	assert.NoError(t, err)
+	assert.Equal(t, data, nested)
+
+	// indexes are placed in numeric order
+	flat := make(map[string]interface{})
+	expected := make([]interface{}, 12)
+	for index := range expected {
+		flat["a."+strconv.Itoa(index)] = index
+		expected[index] = index
+	}
+	nested, err = Unflatten(flat, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": expected}, nested)
+
+	// sparse indexes
+	_, err = Unflatten(map[string]interface{}{"a.2": "x"}, nil)
+	assert.Error(t, err)
+
+	_, err = Unflatten(map[string]interface{}{"a.0": "x", "a.1000000000": "y"}, nil)
+	assert.Error(t, err)
+
+	// nested brackets
+	nested, err = Unflatten(map[string]interface{}{"a[0][0]": "x", "a[0][1]": "y"}, &FlattenOptions{IndexStyle: FlattenIndexBracket})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{[]interface{}{"x", "y"}}}, nested)
+
+	_, err = Unflatten(map[string]interface{}{"a[0][1]": "x"}, &FlattenOptions{IndexStyle: FlattenIndexBracket})
+	assert.Error(t, err)
+
+	// conflicts
+	_, err = Unflatten(map[string]interface{}{"a": 1, "a.b": 2}, nil)
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+
+	_, err = Unflatten(map[string]interface{}{"a.0": 1, "a.b": 2}, nil)
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+
+	_, err = Unflatten(map[string]interface{}{"a.0": 1, "a[0]": 2}, &FlattenOptions{IndexStyle: FlattenIndexBracket})
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+
+	// values are never used as containers, even empty maps and nil
+	empty := map[string]interface{}{}
+	_, err = Unflatten(map[string]interface{}{"a": empty, "a.b": 1}, nil)
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+	assert.Equal(t, 0, len(empty))
+
+	_, err = Unflatten(map[string]interface{}{"a": nil, "a.b": 1}, nil)
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+
+	_, err = Unflatten(map[string]interface{}{"a[0]": nil, "a[0].b": 1}, &FlattenOptions{IndexStyle: FlattenIndexBracket})
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+
+	_, err = Unflatten(map[string]interface{}{"a": []interface{}{}, "a.0": 1}, nil)
+	assert.True(t, errors.Is(err, ErrKeyConflict))
+
+	nested, err = Unflatten(map[string]interface{}{"a": nil, "b.c": empty}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": map[string]interface{}{"c": empty}}, nested)
+
+	// invalid keys
+	_, err = Unflatten(map[string]interface{}{"a..b": 1}, nil)
+	assert.Error(t, err)
+
+	_, err = Unflatten(map[string]interface{}{"a[x]": 1}, &FlattenOptions{IndexStyle: FlattenIndexBracket})
+	assert.Error(t, err)
+}
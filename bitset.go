@@ -0,0 +1,466 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// number of bits in each word of the bitset
+const bitsPerWord = 64
+
+//
+// A dense set of non-negative integers stored as bits. The set
+// grows as needed when bits are set. Memory use is proportional
+// to the largest value in the set, which makes it ideal for flags
+// and permission masks over large but dense ID spaces. The zero
+// value is an empty set ready to use. The bitset is not safe for
+// concurrent use.
+//
+type BitSet struct {
+	words []uint64
+}
+
+//
+// Create a new empty bitset with room for bits `0` to
+// `size - 1` before it needs to grow.
+//
+func NewBitSet(size uint) *BitSet {
+	return &BitSet{
+		words: make([]uint64, (size+bitsPerWord-1)/bitsPerWord),
+	}
+}
+
+//
+// Create a new bitset with the given bits set.
+//
+func NewBitSetOf(values ...uint) *BitSet {
+	set := &BitSet{}
+	for _, value := range values {
+		set.Set(value)
+	}
+
+	return set
+}
+
+// grow the words to hold the given bit
+func (set *BitSet) ensure(bit uint) {
+	needed := int(bit/bitsPerWord) + 1
+	if needed <= len(set.words) {
+		return
+	}
+
+	if needed <= cap(set.words) {
+		set.words = set.words[:needed]
+		return
+	}
+
+	words := make([]uint64, needed, needed*2)
+	copy(words, set.words)
+	set.words = words
+}
+
+// remove trailing zero words
+func (set *BitSet) trim() {
+	length := len(set.words)
+	for length > 0 && set.words[length-1] == 0 {
+		length--
+	}
+
+	set.words = set.words[:length]
+}
+
+//
+// Set the bit, adding the value to the set.
+//
+func (set *BitSet) Set(bit uint) *BitSet {
+	set.ensure(bit)
+	set.words[bit/bitsPerWord] |= 1 << (bit % bitsPerWord)
+	return set
+}
+
+//
+// Clear the bit, removing the value from the set.
+//
+func (set *BitSet) Clear(bit uint) *BitSet {
+	if index := bit / bitsPerWord; index < uint(len(set.words)) {
+		set.words[index] &^= 1 << (bit % bitsPerWord)
+	}
+
+	return set
+}
+
+//
+// Flip the bit, adding the value if absent and removing it
+// if present.
+//
+func (set *BitSet) Flip(bit uint) *BitSet {
+	set.ensure(bit)
+	set.words[bit/bitsPerWord] ^= 1 << (bit % bitsPerWord)
+	return set
+}
+
+//
+// Check if the bit is set, that is, the value is in the set.
+//
+func (set *BitSet) Test(bit uint) bool {
+	index := bit / bitsPerWord
+	if index >= uint(len(set.words)) {
+		return false
+	}
+
+	return set.words[index]&(1<<(bit%bitsPerWord)) != 0
+}
+
+//
+// Set all bits from `start` up to but not including `end`.
+//
+func (set *BitSet) SetRange(start uint, end uint) *BitSet {
+	if start >= end {
+		return set
+	}
+
+	set.ensure(end - 1)
+	set.applyRange(start, end, func(word *uint64, mask uint64) {
+		*word |= mask
+	})
+	return set
+}
+
+//
+// Clear all bits from `start` up to but not including `end`.
+//
+func (set *BitSet) ClearRange(start uint, end uint) *BitSet {
+	limit := uint(len(set.words)) * bitsPerWord
+	if end > limit {
+		end = limit
+	}
+
+	if start >= end {
+		return set
+	}
+
+	set.applyRange(start, end, func(word *uint64, mask uint64) {
+		*word &^= mask
+	})
+	return set
+}
+
+// call the operation with a mask for each word in the range
+func (set *BitSet) applyRange(start uint, end uint, operation func(word *uint64, mask uint64)) {
+	first := start / bitsPerWord
+	last := (end - 1) / bitsPerWord
+	for index := first; index <= last; index++ {
+		mask := ^uint64(0)
+		if index == first {
+			mask &= ^uint64(0) << (start % bitsPerWord)
+		}
+		if index == last {
+			mask &= ^uint64(0) >> (bitsPerWord - 1 - (end-1)%bitsPerWord)
+		}
+
+		operation(&set.words[index], mask)
+	}
+}
+
+//
+// Return the number of bits set, that is, the number of
+// values in the set.
+//
+func (set *BitSet) Count() int {
+	count := 0
+	for _, word := range set.words {
+		count += bits.OnesCount64(word)
+	}
+
+	return count
+}
+
+//
+// Check if no bit is set.
+//
+func (set *BitSet) IsEmpty() bool {
+	for _, word := range set.words {
+		if word != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// Return the first set bit at or after `from`, and `false`
+// if there is none.
+//
+func (set *BitSet) NextSet(from uint) (uint, bool) {
+	index := from / bitsPerWord
+	if index >= uint(len(set.words)) {
+		return 0, false
+	}
+
+	word := set.words[index] >> (from % bitsPerWord)
+	if word != 0 {
+		return from + uint(bits.TrailingZeros64(word)), true
+	}
+
+	for index++; index < uint(len(set.words)); index++ {
+		if set.words[index] != 0 {
+			return index*bitsPerWord + uint(bits.TrailingZeros64(set.words[index])), true
+		}
+	}
+
+	return 0, false
+}
+
+//
+// Return the first clear bit at or after `from`. As the set
+// is unbounded there is always one.
+//
+func (set *BitSet) NextClear(from uint) uint {
+	index := from / bitsPerWord
+	if index >= uint(len(set.words)) {
+		return from
+	}
+
+	word := ^set.words[index] >> (from % bitsPerWord)
+	if word != 0 {
+		return from + uint(bits.TrailingZeros64(word))
+	}
+
+	for index++; index < uint(len(set.words)); index++ {
+		if set.words[index] != ^uint64(0) {
+			return index*bitsPerWord + uint(bits.TrailingZeros64(^set.words[index]))
+		}
+	}
+
+	return uint(len(set.words)) * bitsPerWord
+}
+
+//
+// Call the consumer for each set bit in ascending order,
+// stopping when it returns `false`.
+//
+func (set *BitSet) ForEach(consumer func(bit uint) bool) {
+	for index, word := range set.words {
+		for word != 0 {
+			offset := uint(bits.TrailingZeros64(word))
+			if !consumer(uint(index)*bitsPerWord + offset) {
+				return
+			}
+			word &= word - 1
+		}
+	}
+}
+
+//
+// Return an iterator over the set bits in ascending order.
+// The bitset must not be modified while iterating.
+//
+func (set *BitSet) Iterator() Iterator[uint] {
+	next := uint(0)
+	return IteratorFunc[uint](func() (uint, bool) {
+		bit, found := set.NextSet(next)
+		if found {
+			next = bit + 1
+		}
+		return bit, found
+	})
+}
+
+//
+// Return the set bits in ascending order.
+//
+func (set *BitSet) ToSlice() []uint {
+	result := make([]uint, 0, set.Count())
+	set.ForEach(func(bit uint) bool {
+		result = append(result, bit)
+		return true
+	})
+
+	return result
+}
+
+//
+// Add all bits of `other` to this set, in place.
+//
+func (set *BitSet) Union(other *BitSet) *BitSet {
+	if len(other.words) > len(set.words) {
+		set.ensure(uint(len(other.words))*bitsPerWord - 1)
+	}
+
+	for index, word := range other.words {
+		set.words[index] |= word
+	}
+
+	return set
+}
+
+//
+// Keep only the bits that are also set in `other`, in place.
+//
+func (set *BitSet) Intersection(other *BitSet) *BitSet {
+	for index := range set.words {
+		if index < len(other.words) {
+			set.words[index] &= other.words[index]
+		} else {
+			set.words[index] = 0
+		}
+	}
+
+	set.trim()
+	return set
+}
+
+//
+// Remove all bits that are set in `other`, in place.
+//
+func (set *BitSet) Difference(other *BitSet) *BitSet {
+	for index := 0; index < len(set.words) && index < len(other.words); index++ {
+		set.words[index] &^= other.words[index]
+	}
+
+	set.trim()
+	return set
+}
+
+//
+// Keep the bits that are set in exactly one of the two sets,
+// in place.
+//
+func (set *BitSet) SymmetricDifference(other *BitSet) *BitSet {
+	if len(other.words) > len(set.words) {
+		set.ensure(uint(len(other.words))*bitsPerWord - 1)
+	}
+
+	for index, word := range other.words {
+		set.words[index] ^= word
+	}
+
+	set.trim()
+	return set
+}
+
+//
+// Check if both sets contain the same bits.
+//
+func (set *BitSet) Equal(other *BitSet) bool {
+	longer, shorter := set.words, other.words
+	if len(shorter) > len(longer) {
+		longer, shorter = shorter, longer
+	}
+
+	for index, word := range longer {
+		if index < len(shorter) {
+			if word != shorter[index] {
+				return false
+			}
+		} else if word != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// Return a copy of the set.
+//
+func (set *BitSet) Clone() *BitSet {
+	words := make([]uint64, len(set.words))
+	copy(words, set.words)
+	return &BitSet{words: words}
+}
+
+//
+// Return the set as a string like `{1, 5, 9}`.
+//
+func (set *BitSet) String() string {
+	var builder strings.Builder
+	builder.WriteByte('{')
+	set.ForEach(func(bit uint) bool {
+		if builder.Len() > 1 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(strconv.FormatUint(uint64(bit), 10))
+		return true
+	})
+	builder.WriteByte('}')
+
+	return builder.String()
+}
+
+//
+// Encode the set as little-endian 64-bit words, with trailing
+// zero words omitted.
+//
+func (set *BitSet) MarshalBinary() ([]byte, error) {
+	length := len(set.words)
+	for length > 0 && set.words[length-1] == 0 {
+		length--
+	}
+
+	data := make([]byte, length*8)
+	for index := 0; index < length; index++ {
+		binary.LittleEndian.PutUint64(data[index*8:], set.words[index])
+	}
+
+	return data, nil
+}
+
+//
+// Decode the set from the format written by `MarshalBinary`,
+// replacing the existing bits.
+//
+func (set *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return errors.New("BitSet data length must be a multiple of 8")
+	}
+
+	words := make([]uint64, len(data)/8)
+	for index := range words {
+		words[index] = binary.LittleEndian.Uint64(data[index*8:])
+	}
+
+	set.words = words
+	return nil
+}
+
+//
+// Encode the set as a URL-safe base64 string of its binary form.
+//
+func (set *BitSet) ToBase64() string {
+	data, _ := set.MarshalBinary()
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+//
+// Decode a set from the string written by `ToBase64`.
+//
+func BitSetFromBase64(encoded string) (*BitSet, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &BitSet{}
+	if err := set.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
@@ -0,0 +1,154 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitSet(t *testing.T) {
+	var set BitSet
+	assert.True(t, set.IsEmpty())
+	assert.False(t, set.Test(5))
+	assert.Equal(t, "{}", set.String())
+
+	set.Set(1).Set(64).Set(200)
+	assert.True(t, set.Test(1))
+	assert.True(t, set.Test(64))
+	assert.True(t, set.Test(200))
+	assert.False(t, set.Test(2))
+	assert.False(t, set.Test(1000))
+	assert.Equal(t, 3, set.Count())
+	assert.Equal(t, "{1, 64, 200}", set.String())
+
+	set.Clear(64).Clear(5000)
+	assert.False(t, set.Test(64))
+	assert.Equal(t, []uint{1, 200}, set.ToSlice())
+
+	set.Flip(1).Flip(2)
+	assert.Equal(t, []uint{2, 200}, set.ToSlice())
+
+	assert.Equal(t, []uint{3, 4}, NewBitSet(128).Set(3).Set(4).ToSlice())
+}
+
+func TestBitSetRanges(t *testing.T) {
+	set := NewBitSet(0)
+	set.SetRange(60, 130)
+	assert.Equal(t, 70, set.Count())
+	assert.False(t, set.Test(59))
+	assert.True(t, set.Test(60))
+	assert.True(t, set.Test(129))
+	assert.False(t, set.Test(130))
+
+	set.ClearRange(64, 128)
+	assert.Equal(t, []uint{60, 61, 62, 63, 128, 129}, set.ToSlice())
+
+	// empty and out of range
+	set.SetRange(10, 10)
+	set.ClearRange(500, 600)
+	assert.Equal(t, 6, set.Count())
+
+	set.SetRange(0, 64)
+	assert.Equal(t, 66, set.Count())
+	set.ClearRange(0, 1000)
+	assert.True(t, set.IsEmpty())
+}
+
+func TestBitSetSearch(t *testing.T) {
+	set := NewBitSetOf(3, 64, 65, 300)
+
+	bit, found := set.NextSet(0)
+	assert.True(t, found)
+	assert.Equal(t, uint(3), bit)
+
+	bit, found = set.NextSet(4)
+	assert.True(t, found)
+	assert.Equal(t, uint(64), bit)
+
+	bit, found = set.NextSet(66)
+	assert.True(t, found)
+	assert.Equal(t, uint(300), bit)
+
+	_, found = set.NextSet(301)
+	assert.False(t, found)
+	_, found = set.NextSet(10000)
+	assert.False(t, found)
+
+	assert.Equal(t, uint(0), set.NextClear(0))
+	assert.Equal(t, uint(4), set.NextClear(3))
+	assert.Equal(t, uint(66), set.NextClear(64))
+	assert.Equal(t, uint(5000), set.NextClear(5000))
+
+	full := NewBitSet(0).SetRange(0, 128)
+	assert.Equal(t, uint(128), full.NextClear(0))
+
+	assert.Equal(t, []uint{3, 64, 65, 300}, NewStream(set.Iterator()).Collect())
+
+	visited := make([]uint, 0)
+	set.ForEach(func(bit uint) bool {
+		visited = append(visited, bit)
+		return bit < 64
+	})
+	assert.Equal(t, []uint{3, 64}, visited)
+}
+
+func TestBitSetOperations(t *testing.T) {
+	a := NewBitSetOf(1, 2, 3, 100)
+	b := NewBitSetOf(2, 3, 4, 500)
+
+	assert.Equal(t, []uint{1, 2, 3, 4, 100, 500}, a.Clone().Union(b).ToSlice())
+	assert.Equal(t, []uint{2, 3}, a.Clone().Intersection(b).ToSlice())
+	assert.Equal(t, []uint{1, 100}, a.Clone().Difference(b).ToSlice())
+	assert.Equal(t, []uint{1, 4, 100, 500}, a.Clone().SymmetricDifference(b).ToSlice())
+
+	// originals untouched
+	assert.Equal(t, []uint{1, 2, 3, 100}, a.ToSlice())
+
+	// equality ignores capacity
+	assert.True(t, NewBitSetOf(1, 2).Equal(NewBitSet(1000).Set(1).Set(2)))
+	assert.True(t, NewBitSet(1000).Set(1).Equal(NewBitSetOf(1)))
+	assert.False(t, NewBitSetOf(1, 2).Equal(NewBitSetOf(1)))
+	assert.False(t, NewBitSetOf(1, 900).Equal(NewBitSetOf(1)))
+	assert.True(t, a.Clone().SymmetricDifference(a).IsEmpty())
+}
+
+func TestBitSetSerialization(t *testing.T) {
+	set := NewBitSetOf(0, 7, 64, 1000)
+	set.ensure(5000)
+
+	data, err := set.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, 16*8, len(data))
+
+	decoded := NewBitSetOf(3)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.True(t, set.Equal(decoded))
+	assert.False(t, decoded.Test(3))
+
+	assert.Error(t, decoded.UnmarshalBinary([]byte{1, 2, 3}))
+
+	encoded := set.ToBase64()
+	fromBase64, err := BitSetFromBase64(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{0, 7, 64, 1000}, fromBase64.ToSlice())
+
+	empty, err := BitSetFromBase64(NewBitSet(0).ToBase64())
+	assert.NoError(t, err)
+	assert.True(t, empty.IsEmpty())
+
+	_, err = BitSetFromBase64("!!!")
+	assert.Error(t, err)
+	_, err = BitSetFromBase64("AQI")
+	assert.Error(t, err)
+}
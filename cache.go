@@ -0,0 +1,619 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+//
+// Defines why an entry was removed from a cache.
+//
+type EvictionReason int
+
+const (
+	EvictionCapacity EvictionReason = iota // evicted to honor the entry or cost bound
+	EvictionExpired                        // the time-to-live of the entry has passed
+)
+
+//
+// Options for creating a cache. The zero value creates an
+// unbounded cache whose entries never expire.
+//
+type CacheOptions[K comparable, V any] struct {
+	MaxEntries int                                         // maximum number of entries, `0` means no limit
+	MaxCost    int64                                       // maximum total cost of entries, `0` means no limit
+	Cost       func(key K, value V) int64                  // cost of an entry, defaults to `1` for each entry
+	DefaultTTL time.Duration                               // time-to-live for `Put`, `0` means entries never expire
+	OnEvict    func(key K, value V, reason EvictionReason) // called when an entry is evicted or expires
+	Now        func() time.Time                            // source of the current time, defaults to `time.Now`
+}
+
+//
+// Counters describing the effectiveness of a cache.
+//
+type CacheStats struct {
+	Hits        uint64 // number of lookups that found a live entry
+	Misses      uint64 // number of lookups that found no live entry
+	Evictions   uint64 // number of entries evicted due to bounds
+	Expirations uint64 // number of entries removed after their TTL
+}
+
+//
+// Return the fraction of lookups that were hits, or `0` if
+// there have been no lookups.
+//
+func (stats CacheStats) HitRatio() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(stats.Hits) / float64(total)
+}
+
+// a single entry in the cache
+type cacheEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	cost      int64
+	expiresAt time.Time
+
+	// used by the LRU policy
+	element *list.Element
+
+	// used by the LFU policy
+	frequency uint64
+	tick      uint64
+	handle    *PriorityQueueHandle[*cacheEntry[K, V]]
+}
+
+// decides which entry to evict next
+type cachePolicy[K comparable, V any] interface {
+	add(entry *cacheEntry[K, V])
+	access(entry *cacheEntry[K, V])
+	remove(entry *cacheEntry[K, V])
+	victim(keep *cacheEntry[K, V]) *cacheEntry[K, V]
+	clear()
+}
+
+// evicts the least recently used entry
+type lruPolicy[K comparable, V any] struct {
+	order *list.List
+}
+
+func (policy *lruPolicy[K, V]) add(entry *cacheEntry[K, V]) {
+	entry.element = policy.order.PushFront(entry)
+}
+
+func (policy *lruPolicy[K, V]) access(entry *cacheEntry[K, V]) {
+	policy.order.MoveToFront(entry.element)
+}
+
+func (policy *lruPolicy[K, V]) remove(entry *cacheEntry[K, V]) {
+	policy.order.Remove(entry.element)
+	entry.element = nil
+}
+
+func (policy *lruPolicy[K, V]) victim(keep *cacheEntry[K, V]) *cacheEntry[K, V] {
+	back := policy.order.Back()
+	if back != nil && back.Value == keep {
+		back = back.Prev()
+	}
+
+	if back == nil {
+		return nil
+	}
+
+	return back.Value.(*cacheEntry[K, V])
+}
+
+func (policy *lruPolicy[K, V]) clear() {
+	policy.order.Init()
+}
+
+// evicts the least frequently used entry, the least recently
+// used one among entries with the same frequency
+type lfuPolicy[K comparable, V any] struct {
+	queue *PriorityQueue[*cacheEntry[K, V]]
+	ticks uint64
+}
+
+func newLFUPolicy[K comparable, V any]() *lfuPolicy[K, V] {
+	return &lfuPolicy[K, V]{
+		queue: NewPriorityQueue(func(a *cacheEntry[K, V], b *cacheEntry[K, V]) bool {
+			if a.frequency != b.frequency {
+				return a.frequency < b.frequency
+			}
+
+			return a.tick < b.tick
+		}),
+	}
+}
+
+func (policy *lfuPolicy[K, V]) add(entry *cacheEntry[K, V]) {
+	policy.ticks++
+	entry.frequency = 1
+	entry.tick = policy.ticks
+	entry.handle = policy.queue.Push(entry)
+}
+
+func (policy *lfuPolicy[K, V]) access(entry *cacheEntry[K, V]) {
+	policy.ticks++
+	entry.frequency++
+	entry.tick = policy.ticks
+	policy.queue.Update(entry.handle, entry)
+}
+
+func (policy *lfuPolicy[K, V]) remove(entry *cacheEntry[K, V]) {
+	policy.queue.Remove(entry.handle)
+	entry.handle = nil
+}
+
+func (policy *lfuPolicy[K, V]) victim(keep *cacheEntry[K, V]) *cacheEntry[K, V] {
+	items := policy.queue.heap.items
+	if len(items) == 0 {
+		return nil
+	}
+
+	if items[0].value != keep {
+		return items[0].value
+	}
+
+	// the next candidate is one of the children of the root
+	var victim *cacheEntry[K, V]
+	for index := 1; index <= 2 && index < len(items); index++ {
+		if victim == nil || policy.queue.less(items[index].value, victim) {
+			victim = items[index].value
+		}
+	}
+
+	return victim
+}
+
+func (policy *lfuPolicy[K, V]) clear() {
+	policy.queue = newLFUPolicy[K, V]().queue
+}
+
+//
+// An in-memory cache with a pluggable eviction policy, created
+// using `NewLRUCache`, `NewLFUCache` or `NewTTLCache`. Entries are
+// evicted when the entry or cost bounds are exceeded, and expired
+// entries are removed lazily on access or by `RemoveExpired`. The
+// cache is not safe for concurrent use, wrap it using
+// `NewSyncCache` to share it between goroutines.
+//
+type Cache[K comparable, V any] struct {
+	options CacheOptions[K, V]
+	entries map[K]*cacheEntry[K, V]
+	policy  cachePolicy[K, V]
+	cost    int64
+	stats   CacheStats
+}
+
+// create a new cache with the given policy
+func newCache[K comparable, V any](options CacheOptions[K, V], policy cachePolicy[K, V]) *Cache[K, V] {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return &Cache[K, V]{
+		options: options,
+		entries: make(map[K]*cacheEntry[K, V]),
+		policy:  policy,
+	}
+}
+
+//
+// Create a new cache that evicts the least recently used
+// entry when full.
+//
+func NewLRUCache[K comparable, V any](options CacheOptions[K, V]) *Cache[K, V] {
+	return newCache[K, V](options, &lruPolicy[K, V]{order: list.New()})
+}
+
+//
+// Create a new cache that evicts the least frequently used
+// entry when full. Among entries used equally often, the least
+// recently used one is evicted.
+//
+func NewLFUCache[K comparable, V any](options CacheOptions[K, V]) *Cache[K, V] {
+	return newCache[K, V](options, newLFUPolicy[K, V]())
+}
+
+//
+// Create a new cache where every entry expires after the given
+// time-to-live. If the cache is bounded, the least recently used
+// entry is evicted when full.
+//
+func NewTTLCache[K comparable, V any](ttl time.Duration, options CacheOptions[K, V]) *Cache[K, V] {
+	options.DefaultTTL = ttl
+	return NewLRUCache(options)
+}
+
+//
+// Return the value for the key, and `false` if the key is not
+// present or has expired.
+//
+func (cache *Cache[K, V]) Get(key K) (V, bool) {
+	entry, found := cache.entries[key]
+	if found && cache.isExpired(entry, cache.options.Now()) {
+		cache.removeEntry(entry, EvictionExpired)
+		found = false
+	}
+
+	if !found {
+		cache.stats.Misses++
+
+		var zero V
+		return zero, false
+	}
+
+	cache.stats.Hits++
+	cache.policy.access(entry)
+	return entry.value, true
+}
+
+//
+// Return the value for the key without updating its usage or
+// the statistics, and `false` if the key is not present or has
+// expired.
+//
+func (cache *Cache[K, V]) Peek(key K) (V, bool) {
+	entry, found := cache.entries[key]
+	if !found || cache.isExpired(entry, cache.options.Now()) {
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+//
+// Check if the key is present and has not expired. The usage
+// of the entry is not updated.
+//
+func (cache *Cache[K, V]) Contains(key K) bool {
+	_, found := cache.Peek(key)
+	return found
+}
+
+//
+// Add or replace the value for the key using the default
+// time-to-live. Returns `false` if the entry alone exceeds the
+// maximum cost and was not stored.
+//
+func (cache *Cache[K, V]) Put(key K, value V) bool {
+	return cache.PutWithTTL(key, value, cache.options.DefaultTTL)
+}
+
+//
+// Add or replace the value for the key with the given
+// time-to-live. A `ttl` of `0` or less means the entry never
+// expires. Returns `false` if the entry alone exceeds the maximum
+// cost and was not stored.
+//
+func (cache *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) bool {
+	cost := int64(1)
+	if cache.options.Cost != nil {
+		cost = cache.options.Cost(key, value)
+	}
+
+	if cache.options.MaxCost > 0 && cost > cache.options.MaxCost {
+		return false
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = cache.options.Now().Add(ttl)
+	}
+
+	entry, found := cache.entries[key]
+	if found {
+		cache.cost += cost - entry.cost
+		entry.value = value
+		entry.cost = cost
+		entry.expiresAt = expiresAt
+		cache.policy.access(entry)
+	} else {
+		entry = &cacheEntry[K, V]{key: key, value: value, cost: cost, expiresAt: expiresAt}
+		cache.entries[key] = entry
+		cache.cost += cost
+		cache.policy.add(entry)
+	}
+
+	cache.enforceBounds(entry)
+	return true
+}
+
+//
+// Remove the key from the cache. Returns `false` if the key was
+// not present. The eviction callback is not called.
+//
+func (cache *Cache[K, V]) Remove(key K) bool {
+	entry, found := cache.entries[key]
+	if !found {
+		return false
+	}
+
+	cache.policy.remove(entry)
+	delete(cache.entries, key)
+	cache.cost -= entry.cost
+	return true
+}
+
+//
+// Remove all expired entries and return how many were removed.
+// Call this periodically, or use `SyncCache.StartJanitor`, to
+// reclaim memory held by entries that are never read again.
+//
+func (cache *Cache[K, V]) RemoveExpired() int {
+	now := cache.options.Now()
+	removed := 0
+	for _, entry := range cache.entries {
+		if cache.isExpired(entry, now) {
+			cache.removeEntry(entry, EvictionExpired)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+//
+// Return the number of entries in the cache, including expired
+// entries that have not yet been removed.
+//
+func (cache *Cache[K, V]) Len() int {
+	return len(cache.entries)
+}
+
+//
+// Return the total cost of the entries in the cache.
+//
+func (cache *Cache[K, V]) Cost() int64 {
+	return cache.cost
+}
+
+//
+// Return the keys in the cache in no particular order,
+// including expired entries that have not yet been removed.
+//
+func (cache *Cache[K, V]) Keys() []K {
+	return MapKeys(cache.entries)
+}
+
+//
+// Remove all entries from the cache. The eviction callback is
+// not called and the statistics are retained.
+//
+func (cache *Cache[K, V]) Clear() {
+	cache.entries = make(map[K]*cacheEntry[K, V])
+	cache.policy.clear()
+	cache.cost = 0
+}
+
+//
+// Return the statistics of the cache.
+//
+func (cache *Cache[K, V]) Stats() CacheStats {
+	return cache.stats
+}
+
+// check if the entry has expired
+func (cache *Cache[K, V]) isExpired(entry *cacheEntry[K, V], now time.Time) bool {
+	return !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt)
+}
+
+// remove the entry and notify the callback
+func (cache *Cache[K, V]) removeEntry(entry *cacheEntry[K, V], reason EvictionReason) {
+	cache.policy.remove(entry)
+	delete(cache.entries, entry.key)
+	cache.cost -= entry.cost
+
+	if reason == EvictionExpired {
+		cache.stats.Expirations++
+	} else {
+		cache.stats.Evictions++
+	}
+
+	if cache.options.OnEvict != nil {
+		cache.options.OnEvict(entry.key, entry.value, reason)
+	}
+}
+
+// evict entries until the bounds are honored, never evicting the given entry
+func (cache *Cache[K, V]) enforceBounds(keep *cacheEntry[K, V]) {
+	for cache.overBounds() {
+		victim := cache.policy.victim(keep)
+		if victim == nil {
+			return
+		}
+
+		cache.removeEntry(victim, EvictionCapacity)
+	}
+}
+
+// check if the cache exceeds any of its bounds
+func (cache *Cache[K, V]) overBounds() bool {
+	if cache.options.MaxEntries > 0 && len(cache.entries) > cache.options.MaxEntries {
+		return true
+	}
+
+	return cache.options.MaxCost > 0 && cache.cost > cache.options.MaxCost
+}
+
+//
+// A thread-safe wrapper around a `Cache`. All methods lock the
+// cache, as even a `Get` updates the usage of an entry. The
+// eviction callback is called while the lock is held and must not
+// call back into the cache.
+//
+type SyncCache[K comparable, V any] struct {
+	mutex sync.Mutex
+	cache *Cache[K, V]
+}
+
+//
+// Wrap the cache for concurrent use. The wrapped cache must not
+// be used directly afterwards.
+//
+func NewSyncCache[K comparable, V any](cache *Cache[K, V]) *SyncCache[K, V] {
+	return &SyncCache[K, V]{
+		cache: cache,
+	}
+}
+
+//
+// See `Cache.Get`.
+//
+func (sc *SyncCache[K, V]) Get(key K) (V, bool) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Get(key)
+}
+
+//
+// See `Cache.Peek`.
+//
+func (sc *SyncCache[K, V]) Peek(key K) (V, bool) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Peek(key)
+}
+
+//
+// See `Cache.Contains`.
+//
+func (sc *SyncCache[K, V]) Contains(key K) bool {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Contains(key)
+}
+
+//
+// See `Cache.Put`.
+//
+func (sc *SyncCache[K, V]) Put(key K, value V) bool {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Put(key, value)
+}
+
+//
+// See `Cache.PutWithTTL`.
+//
+func (sc *SyncCache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) bool {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.PutWithTTL(key, value, ttl)
+}
+
+//
+// See `Cache.Remove`.
+//
+func (sc *SyncCache[K, V]) Remove(key K) bool {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Remove(key)
+}
+
+//
+// See `Cache.RemoveExpired`.
+//
+func (sc *SyncCache[K, V]) RemoveExpired() int {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.RemoveExpired()
+}
+
+//
+// See `Cache.Len`.
+//
+func (sc *SyncCache[K, V]) Len() int {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Len()
+}
+
+//
+// See `Cache.Cost`.
+//
+func (sc *SyncCache[K, V]) Cost() int64 {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Cost()
+}
+
+//
+// See `Cache.Keys`.
+//
+func (sc *SyncCache[K, V]) Keys() []K {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Keys()
+}
+
+//
+// See `Cache.Clear`.
+//
+func (sc *SyncCache[K, V]) Clear() {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.cache.Clear()
+}
+
+//
+// See `Cache.Stats`.
+//
+func (sc *SyncCache[K, V]) Stats() CacheStats {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.cache.Stats()
+}
+
+//
+// Start a background goroutine that removes expired entries
+// every `interval`. Call the returned function to stop it. An
+// `interval` of zero or less starts nothing and returns a
+// function that does nothing.
+//
+func (sc *SyncCache[K, V]) StartJanitor(interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				sc.RemoveExpired()
+
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// a clock that only moves when told to
+type testClock struct {
+	now time.Time
+}
+
+func (clock *testClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *testClock) Advance(duration time.Duration) {
+	clock.now = clock.now.Add(duration)
+}
+
+func TestLRUCache(t *testing.T) {
+	evicted := make([]string, 0)
+	cache := NewLRUCache(CacheOptions[string, int]{
+		MaxEntries: 2,
+		OnEvict: func(key string, value int, reason EvictionReason) {
+			assert.Equal(t, EvictionCapacity, reason)
+			evicted = append(evicted, key)
+		},
+	})
+
+	assert.True(t, cache.Put("a", 1))
+	assert.True(t, cache.Put("b", 2))
+
+	// touch a so that b is the least recently used
+	value, found := cache.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	cache.Put("c", 3)
+	assert.Equal(t, []string{"b"}, evicted)
+	assert.False(t, cache.Contains("b"))
+	assert.True(t, cache.Contains("a"))
+	assert.True(t, cache.Contains("c"))
+
+	// peek does not change the order
+	_, found = cache.Peek("a")
+	assert.True(t, found)
+	cache.Put("d", 4)
+	assert.Equal(t, []string{"b", "a"}, evicted)
+
+	// replacing does not evict
+	cache.Put("d", 40)
+	value, _ = cache.Get("d")
+	assert.Equal(t, 40, value)
+	assert.Equal(t, 2, cache.Len())
+	assert.ElementsMatch(t, []string{"c", "d"}, cache.Keys())
+
+	_, found = cache.Get("zzz")
+	assert.False(t, found)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Evictions)
+	assert.InDelta(t, 0.666, stats.HitRatio(), 0.001)
+
+	assert.True(t, cache.Remove("c"))
+	assert.False(t, cache.Remove("c"))
+	assert.Equal(t, 1, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, int64(0), cache.Cost())
+	assert.Equal(t, 0.0, CacheStats{}.HitRatio())
+}
+
+func TestLFUCache(t *testing.T) {
+	cache := NewLFUCache(CacheOptions[string, int]{MaxEntries: 3})
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")
+	cache.Get("c")
+	cache.Get("c")
+
+	// b is the least frequently used
+	cache.Put("d", 4)
+	assert.False(t, cache.Contains("b"))
+	assert.ElementsMatch(t, []string{"a", "c", "d"}, cache.Keys())
+
+	// d was just added with the lowest frequency, but is not evicted
+	// for the next entry; ties are broken by recency
+	cache.Put("e", 5)
+	assert.False(t, cache.Contains("d"))
+	assert.ElementsMatch(t, []string{"a", "c", "e"}, cache.Keys())
+
+	// replacing an entry counts as a use
+	cache.Put("e", 50)
+	cache.Put("e", 500)
+	cache.Put("f", 6)
+	assert.False(t, cache.Contains("a"))
+	assert.ElementsMatch(t, []string{"c", "e", "f"}, cache.Keys())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	cache.Put("g", 7)
+	assert.True(t, cache.Contains("g"))
+}
+
+func TestCacheCost(t *testing.T) {
+	cache := NewLRUCache(CacheOptions[string, string]{
+		MaxCost: 10,
+		Cost: func(key string, value string) int64 {
+			return int64(len(value))
+		},
+	})
+
+	assert.True(t, cache.Put("a", "1234"))
+	assert.True(t, cache.Put("b", "1234"))
+	assert.Equal(t, int64(8), cache.Cost())
+
+	// needs to evict a
+	assert.True(t, cache.Put("c", "123"))
+	assert.False(t, cache.Contains("a"))
+	assert.Equal(t, int64(7), cache.Cost())
+
+	// too large to store at all
+	assert.False(t, cache.Put("d", "12345678901"))
+	assert.Equal(t, 2, cache.Len())
+
+	// growing an entry evicts others
+	assert.True(t, cache.Put("c", "123456789"))
+	assert.Equal(t, []string{"c"}, cache.Keys())
+	assert.Equal(t, int64(9), cache.Cost())
+}
+
+func TestCacheTTL(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	expired := make([]string, 0)
+	cache := NewTTLCache(time.Minute, CacheOptions[string, int]{
+		Now: clock.Now,
+		OnEvict: func(key string, value int, reason EvictionReason) {
+			assert.Equal(t, EvictionExpired, reason)
+			expired = append(expired, key)
+		},
+	})
+
+	cache.Put("a", 1)
+	cache.PutWithTTL("b", 2, time.Hour)
+	cache.PutWithTTL("c", 3, 0)
+	cache.PutWithTTL("d", 4, time.Second)
+
+	clock.Advance(30 * time.Second)
+	_, found := cache.Get("a")
+	assert.True(t, found)
+
+	// lazy expiration
+	assert.False(t, cache.Contains("d"))
+	assert.Equal(t, 4, cache.Len())
+	_, found = cache.Get("d")
+	assert.False(t, found)
+	assert.Equal(t, []string{"d"}, expired)
+	assert.Equal(t, 3, cache.Len())
+
+	// explicit expiration
+	clock.Advance(time.Minute)
+	assert.Equal(t, 1, cache.RemoveExpired())
+	assert.Equal(t, []string{"d", "a"}, expired)
+	assert.ElementsMatch(t, []string{"b", "c"}, cache.Keys())
+
+	clock.Advance(24 * time.Hour)
+	_, found = cache.Peek("b")
+	assert.False(t, found)
+	_, found = cache.Get("c")
+	assert.True(t, found)
+
+	assert.Equal(t, uint64(2), cache.Stats().Expirations)
+}
+
+func TestSyncCache(t *testing.T) {
+	cache := NewSyncCache(NewLRUCache(CacheOptions[string, int]{MaxEntries: 50}))
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for index := 0; index < 200; index++ {
+				key := strconv.Itoa(index % 70)
+				cache.Put(key, index)
+				cache.Get(key)
+				cache.Contains(key)
+				cache.Peek(key)
+				if index%10 == 0 {
+					cache.Remove(key)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, cache.Len(), 50)
+	assert.Equal(t, int64(cache.Len()), cache.Cost())
+	assert.Equal(t, cache.Len(), len(cache.Keys()))
+	assert.Equal(t, uint64(1600), cache.Stats().Hits)
+
+	cache.PutWithTTL("x", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, 1, cache.RemoveExpired())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestSyncCacheJanitor(t *testing.T) {
+	cache := NewSyncCache(NewTTLCache(time.Millisecond, CacheOptions[string, int]{}))
+	cache.Put("a", 1)
+
+	stop := cache.StartJanitor(time.Millisecond)
+	defer stop()
+
+	assert.Eventually(t, func() bool {
+		return cache.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	stop()
+
+	// intervals of zero or less start nothing
+	cache.Put("b", 2)
+	for _, interval := range []time.Duration{0, -time.Second} {
+		noop := cache.StartJanitor(interval)
+		noop()
+		noop()
+	}
+	assert.Equal(t, 1, cache.Len())
+}
@@ -11,7 +11,10 @@
 
 package berry
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 //
 // Defines the primitive types that define the
@@ -21,6 +24,14 @@ type Number interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
 }
 
+//
+// Defines the types that support the ordering operators
+// `<`, `<=`, `>` and `>=`.
+//
+type Ordered interface {
+	Number | ~string
+}
+
 //
 // Find the minimum value from the slice of numbers. Returns
 // an `error` if the slice is nil, or is empty.
@@ -127,13 +138,24 @@ func SliceIndex[T comparable](slice []T, element T) int {
 	return -1
 }
 
+//
+// Defines how `SliceModifyWithError` treats the slice when
+// the modifier returns an error.
+//
+type ModifyFailureMode int
+
+const (
+	ModifyRollback ModifyFailureMode = iota // restore all elements to their original values
+	ModifyPartial                           // keep the elements modified before the failure
+)
+
 //
 // Modify the slice using a modifier function. Each element of
 // the slice is sent to the modifier, and the returned value is
 // updated in the slice at same index. The method has no effect
 // if the slice is `nil`.
 //
-func SliceModify[T comparable](slice []T, modifier func(item T) T) bool {
+func SliceModify[T any](slice []T, modifier func(item T) T) bool {
 	if slice == nil {
 		return false
 	}
@@ -147,6 +169,78 @@ func SliceModify[T comparable](slice []T, modifier func(item T) T) bool {
 	return true
 }
 
+//
+// Modify the slice using a modifier function that also receives
+// the index of the element. The returned value is updated in the
+// slice at same index. The method has no effect if the slice
+// is `nil`.
+//
+func SliceModifyIndexed[T any](slice []T, modifier func(index int, item T) T) bool {
+	if slice == nil {
+		return false
+	}
+
+	for index := 0; index < len(slice); index++ {
+		slice[index] = modifier(index, slice[index])
+	}
+
+	return true
+}
+
+//
+// Modify the slice using a modifier function that may fail.
+// Elements are modified in order and processing stops at the
+// first error. With `ModifyRollback` the slice is restored to its
+// original values on failure, with `ModifyPartial` the elements
+// before the failing index retain their modified values. Returns
+// an `error` that includes the failing index, or if the slice
+// is `nil`.
+//
+func SliceModifyWithError[T any](slice []T, modifier func(index int, item T) (T, error), mode ModifyFailureMode) error {
+	if slice == nil {
+		return errors.New("Cannot modify a nil slice")
+	}
+
+	var original []T
+	if mode == ModifyRollback {
+		original = make([]T, len(slice))
+		copy(original, slice)
+	}
+
+	for index := 0; index < len(slice); index++ {
+		updated, err := modifier(index, slice[index])
+		if err != nil {
+			if mode == ModifyRollback {
+				copy(slice, original)
+			}
+
+			return fmt.Errorf("Modifier failed at index %d: %w", index, err)
+		}
+
+		slice[index] = updated
+	}
+
+	return nil
+}
+
+//
+// Return a new slice with the result of calling the modifier on
+// each element of the slice. The original slice is not changed.
+// Returns `nil` if the slice is `nil`.
+//
+func SliceModifyCopy[T any](slice []T, modifier func(index int, item T) T) []T {
+	if slice == nil {
+		return nil
+	}
+
+	result := make([]T, len(slice))
+	for index, item := range slice {
+		result[index] = modifier(index, item)
+	}
+
+	return result
+}
+
 //
 // Check if 2 slices are equal or not. The slices are considered
 // equal if and only if there are of same length, and elements at
@@ -12,6 +12,7 @@
 package berry
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -138,3 +139,74 @@ func TestSliceReverse(t *testing.T) {
 	foo = []int{2, 4, 8}
 	assert.Equal(t, []int{8, 4, 2}, SliceReverse(foo))
 }
+
+func TestSliceModifyAny(t *testing.T) {
+	type server struct {
+		name  string
+		ports []int
+	}
+
+	foo := []server{{name: "a", ports: []int{80}}, {name: "b"}}
+	assert.True(t, SliceModify(foo, func(item server) server {
+		item.ports = append(item.ports, 443)
+		return item
+	}))
+	assert.Equal(t, []int{80, 443}, foo[0].ports)
+	assert.Equal(t, []int{443}, foo[1].ports)
+}
+
+func TestSliceModifyIndexed(t *testing.T) {
+	var foo []int
+	modifier := func(index int, x int) int {
+		return x * index
+	}
+
+	assert.False(t, SliceModifyIndexed(foo, modifier))
+
+	foo = []int{2, 4, 6, 8}
+	assert.True(t, SliceModifyIndexed(foo, modifier))
+	assert.Equal(t, []int{0, 4, 12, 24}, foo)
+}
+
+func TestSliceModifyWithError(t *testing.T) {
+	failure := errors.New("too large")
+	modifier := func(index int, x int) (int, error) {
+		if x > 5 {
+			return 0, failure
+		}
+		return x * 10, nil
+	}
+
+	var foo []int
+	assert.Error(t, SliceModifyWithError(foo, modifier, ModifyRollback))
+
+	// success
+	foo = []int{1, 2, 3}
+	assert.NoError(t, SliceModifyWithError(foo, modifier, ModifyRollback))
+	assert.Equal(t, []int{10, 20, 30}, foo)
+
+	// rollback
+	foo = []int{1, 2, 8, 3}
+	err := SliceModifyWithError(foo, modifier, ModifyRollback)
+	assert.True(t, errors.Is(err, failure))
+	assert.Contains(t, err.Error(), "index 2")
+	assert.Equal(t, []int{1, 2, 8, 3}, foo)
+
+	// partial
+	err = SliceModifyWithError(foo, modifier, ModifyPartial)
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, []int{10, 20, 8, 3}, foo)
+}
+
+func TestSliceModifyCopy(t *testing.T) {
+	var foo []int
+	modifier := func(index int, x int) int {
+		return x + index
+	}
+
+	assert.Nil(t, SliceModifyCopy(foo, modifier))
+
+	foo = []int{2, 4, 6}
+	assert.Equal(t, []int{2, 5, 8}, SliceModifyCopy(foo, modifier))
+	assert.Equal(t, []int{2, 4, 6}, foo)
+}
@@ -12,19 +12,48 @@
 package berry
 
 import (
+	"encoding"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 //
 // Convert the given value represented as `interface{}`
-// to boolean. Returns `false` if value is `nil`. Returns
-// `true` if the value is numeric and not-zero, or if the
-// value is `string` and represents a `bool`, or if the
-// value is non-primitive.
+// to boolean. Returns `false` if value is `nil` or a `nil`
+// pointer. Returns `true` if the value is numeric and not-zero,
+// or if the value is `string` and represents `true`. Strings
+// are matched ignoring case and surrounding whitespace against
+// `1`, `true`, `t`, `yes`, `y`, `on` and `enabled` for `true`,
+// `0`, `false`, `f`, `no`, `n`, `off`, `disabled` and the empty
+// string for `false`, and any tokens added with
+// `RegisterBoolTokens`. Other strings are `false`. Values
+// implementing `fmt.Stringer` or `encoding.TextMarshaler` are
+// matched by their text. Other non-primitive values such as
+// structs, maps and slices are `true`, as this function has
+// always documented; earlier versions returned `false` for them.
 //
 func ConvertToBool(value interface{}) (bool, error) {
+	return ConvertToBoolWithOptions(value, nil)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to boolean as `ConvertToBool` does, using the given options.
+// Returns a `*strconv.NumError` for strings that are not a
+// known token if the options ask for `StrictBool`, and an
+// `ErrUnsupportedConversion` for non-primitive values if they
+// ask to `RejectUnsupported`.
+//
+func ConvertToBoolWithOptions(value interface{}, options *ConvertOptions) (bool, error) {
+	value = primitiveOf(value)
 	if value == nil {
 		return false, nil
 	}
@@ -79,31 +108,94 @@ func ConvertToBool(value interface{}) (bool, error) {
 		return data != 0, nil
 
 	case string:
-		v = strings.ToLower(strings.TrimSpace(v))
-		switch v {
-		case "1", "true", "t":
-			return true, nil
-		default:
-			return false, nil
+		parsed, found := lookupBoolToken(v)
+		if !found && options.strictBool() {
+			return false, &strconv.NumError{Func: "ConvertToBool", Num: v, Err: strconv.ErrSyntax}
 		}
+		return parsed, nil
 
 	case bool:
 		return v, nil
 	}
 
+	if options.rejectUnsupported() {
+		return false, ErrUnsupportedConversion
+	}
+
 	// any non-nil struct is considered true
-	return false, nil
+	return true, nil
+}
+
+// guards the registered boolean tokens
+var boolTokensMutex sync.RWMutex
+
+// the strings recognized by `ConvertToBool`, in lower case
+var boolTokens = map[string]bool{
+	"1": true, "true": true, "t": true, "yes": true, "y": true, "on": true, "enabled": true,
+	"0": false, "false": false, "f": false, "no": false, "n": false, "off": false, "disabled": false, "": false,
+}
+
+//
+// Add strings that `ConvertToBool` recognizes as the given
+// value, such as `ja` and `nein` or `oui` and `non`. Tokens
+// are matched ignoring case and surrounding whitespace, and
+// replace any earlier meaning of the same token. Safe for
+// concurrent use.
+//
+func RegisterBoolTokens(value bool, tokens ...string) {
+	boolTokensMutex.Lock()
+	defer boolTokensMutex.Unlock()
+
+	for _, token := range tokens {
+		boolTokens[strings.ToLower(strings.TrimSpace(token))] = value
+	}
+}
+
+// return the value of the token, and `false` if it is not
+// recognized
+func lookupBoolToken(token string) (bool, bool) {
+	boolTokensMutex.RLock()
+	defer boolTokensMutex.RUnlock()
+
+	value, found := boolTokens[strings.ToLower(strings.TrimSpace(token))]
+	return value, found
 }
 
 //
 // Convert the given value represented as `interface{}`
-// to its string representation.
+// to its string representation. Pointers are followed, `[]byte`
+// is read as text, and values implementing `fmt.Stringer`,
+// `error` or `encoding.TextMarshaler` use the text they return.
+// Returns an empty string for `nil` and `nil` pointers.
 //
 func ConvertToString(value interface{}) string {
-	if value == nil {
+	if isNilValue(value) {
 		return ""
 	}
 
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+
+	switch v := value.(type) {
+	case fmt.Stringer:
+		return v.String()
+
+	case error:
+		return v.Error()
+
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	// print the value pointed to rather than the address
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() == reflect.Ptr {
+		return ConvertToString(reflected.Elem().Interface())
+	}
+
 	return fmt.Sprintf("%v", value)
 }
 
@@ -111,205 +203,1182 @@ func ConvertToString(value interface{}) string {
 // Convert the given value represented as `interface{}`
 // to a `uint64` value. Returns the `defaultValue` is the value
 // is `nil`. Returns an `error` if the value cannot be
-// cast to a number. For non-primitive values, the `defaultValue`
-// is returned.
+// cast to a number. Negative values, and floats that are
+// `NaN` or too large, return a `*NumberRangeError`. Floats are
+// truncated towards zero. Pointers, named types and other
+// non-primitive values are handled as by `ConvertToInt64`.
 //
 func ConvertToUint64(value interface{}, defaultValue uint64) (uint64, error) {
+	return convertToUnsigned(value, defaultValue, 64, "uint64", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint64` value as `ConvertToUint64` does, using the
+// given options. Floats with a fractional part return a
+// `*NumberRangeError` unless the options ask to truncate, and
+// values out of range are clamped if they ask to saturate.
+//
+func ConvertToUint64WithOptions(value interface{}, defaultValue uint64, options *ConvertOptions) (uint64, error) {
+	return convertToUnsigned(value, defaultValue, 64, "uint64", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `int64` value. Returns the `defaultValue` is the value
+// is `nil`. Returns an `error` if the value cannot be
+// cast to a number. Values too large for `int64`, and floats
+// that are `NaN`, return a `*NumberRangeError`. Floats are
+// truncated towards zero. Pointers and named types are read as
+// the value they hold, as are `json.Number`, `big.Int`,
+// `big.Float` and `[]byte`; other values implementing
+// `encoding.TextMarshaler` or `fmt.Stringer` are parsed from
+// their text if it is a number. For other non-primitive values,
+// such as a `time.Time`, the `defaultValue` is returned.
+//
+func ConvertToInt64(value interface{}, defaultValue int64) (int64, error) {
+	return convertToSigned(value, defaultValue, 64, "int64", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `int64` value as `ConvertToInt64` does, using the
+// given options. Floats with a fractional part return a
+// `*NumberRangeError` unless the options ask to truncate, and
+// values out of range are clamped if they ask to saturate.
+// Non-primitive values return an `ErrUnsupportedConversion` if
+// they ask to `RejectUnsupported`.
+//
+func ConvertToInt64WithOptions(value interface{}, defaultValue int64, options *ConvertOptions) (int64, error) {
+	return convertToSigned(value, defaultValue, 64, "int64", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `float64` value. Returns the `defaultValue` is the value
+// is `nil`. Returns an `error` if the value cannot be
+// cast to a number. Pointers, named types and other
+// non-primitive values are handled as by `ConvertToInt64`.
+//
+func ConvertToFloat64(value interface{}, defaultValue float64) (float64, error) {
+	return ConvertToFloat64WithOptions(value, defaultValue, nil)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `float64` value as `ConvertToFloat64` does, parsing
+// strings with the `NumberFormat` of the given options.
+//
+func ConvertToFloat64WithOptions(value interface{}, defaultValue float64, options *ConvertOptions) (float64, error) {
+	value, described := describedPrimitiveOf(value)
 	if value == nil {
 		return defaultValue, nil
 	}
 
+	if text, ok := value.(string); ok {
+		if format := options.numberFormat(); format != nil {
+			parsed, err := ParseNumber(text, format)
+			if err != nil && described {
+				return unsupportedText(defaultValue, options)
+			}
+			if err != nil {
+				return defaultValue, err
+			}
+			value = parsed
+		}
+	}
+
 	switch v := value.(type) {
 	case int:
 		data, _ := value.(int)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case int8:
 		data, _ := value.(int8)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case int16:
 		data, _ := value.(int16)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case int32:
 		data, _ := value.(int32)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case int64:
 		data, _ := value.(int64)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case uint:
 		data, _ := value.(uint)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case uint8:
 		data, _ := value.(uint8)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case uint16:
 		data, _ := value.(uint16)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case uint32:
 		data, _ := value.(uint32)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case uint64:
 		data, _ := value.(uint64)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case float32:
 		data, _ := value.(float32)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case float64:
 		data, _ := value.(float64)
-		return uint64(data), nil
+		return float64(data), nil
 
 	case string:
-		return strconv.ParseUint(v, 10, 64)
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil && described {
+			return unsupportedText(defaultValue, options)
+		}
+		return parsed, err
 	}
 
-	// any non-nil struct is considered true
 	return defaultValue, nil
 }
 
+//
+// Options that control how the `ConvertTo*` functions treat
+// values that cannot be represented exactly in the target type.
+// A `nil` options value uses the defaults.
+//
+type ConvertOptions struct {
+	// clamp values outside the range of the target type to its
+	// minimum or maximum instead of returning an error
+	Saturate bool
+
+	// truncate floats that have a fractional part towards zero
+	// when converting to an integer, instead of returning an error;
+	// strings parsed with a `NumberFormat` must still be exact; the
+	// integer conversions without options, like `ConvertToInt8`,
+	// always truncate
+	Truncate bool
+
+	// return an error for strings that are not a known boolean
+	// token, see `RegisterBoolTokens`, instead of `false`
+	StrictBool bool
+
+	// return `ErrUnsupportedConversion` for non-primitive values
+	// that cannot be converted, instead of the default value, in
+	// `ConvertToBoolWithOptions`, `ConvertToTimeWithOptions`,
+	// `ConvertToDurationWithOptions` and the number conversions
+	RejectUnsupported bool
+
+	// the notations accepted when parsing numbers from strings;
+	// `nil` accepts plain numbers only, see `ParseNumber`
+	NumberFormat *NumberFormat
+
+	// the unit of numeric timestamps converted by `ConvertToTime`;
+	// `EpochAuto` detects it from the magnitude of the value
+	EpochUnit EpochUnit
+
+	// the location of times written without a zone, and of the
+	// times returned by `ConvertToTime`; `nil` reads times without
+	// a zone as UTC and keeps the zone of the value otherwise
+	Location *time.Location
+
+	// the time that relative expressions like `now-1h` are based
+	// on; the zero value uses the current time
+	Now time.Time
+
+	// the separator between elements of strings converted to
+	// slices, like `a,b,c`; empty for `,`
+	Separator string
+}
+
+// the options of the integer conversions without options,
+// which truncate floats as they always have
+var truncateConvertOptions = &ConvertOptions{Truncate: true}
+
+func (options *ConvertOptions) saturate() bool {
+	return options != nil && options.Saturate
+}
+
+func (options *ConvertOptions) truncate() bool {
+	return options != nil && options.Truncate
+}
+
+func (options *ConvertOptions) strictBool() bool {
+	return options != nil && options.StrictBool
+}
+
+func (options *ConvertOptions) rejectUnsupported() bool {
+	return options != nil && options.RejectUnsupported
+}
+
+func (options *ConvertOptions) numberFormat() *NumberFormat {
+	if options == nil {
+		return nil
+	}
+
+	return options.NumberFormat
+}
+
+func (options *ConvertOptions) epochUnit() EpochUnit {
+	if options == nil {
+		return EpochAuto
+	}
+
+	return options.EpochUnit
+}
+
+func (options *ConvertOptions) location() *time.Location {
+	if options == nil {
+		return nil
+	}
+
+	return options.Location
+}
+
+func (options *ConvertOptions) separator() string {
+	if options == nil || options.Separator == "" {
+		return ","
+	}
+
+	return options.Separator
+}
+
+func (options *ConvertOptions) now() time.Time {
+	if options == nil || options.Now.IsZero() {
+		return time.Now()
+	}
+
+	return options.Now
+}
+
+//
+// Describes why a number could not be represented in the
+// target type.
+//
+type NumberRangeReason int
+
+const (
+	// the value is larger than the maximum of the target type
+	NumberOverflow NumberRangeReason = iota
+
+	// the value is smaller than the minimum of the target type
+	NumberUnderflow
+
+	// the value is negative and the target type is unsigned
+	NumberNegative
+
+	// the value is a float that is not a number
+	NumberNaN
+
+	// the value is a float with a fractional part
+	NumberFraction
+)
+
+func (reason NumberRangeReason) String() string {
+	switch reason {
+	case NumberOverflow:
+		return "value overflows"
+	case NumberUnderflow:
+		return "value underflows"
+	case NumberNegative:
+		return "value is negative"
+	case NumberNaN:
+		return "value is NaN"
+	case NumberFraction:
+		return "value has a fractional part"
+	}
+
+	return "unknown reason"
+}
+
+//
+// Returned by the `ConvertTo*` functions when a number cannot
+// be represented in the target type without losing information.
+//
+type NumberRangeError struct {
+	// the value that was being converted
+	Value interface{}
+
+	// the name of the target type, like `int8`
+	Target string
+
+	// why the value could not be converted
+	Reason NumberRangeReason
+}
+
+func (err *NumberRangeError) Error() string {
+	return fmt.Sprintf("Cannot convert %v to %s: %s", err.Value, err.Target, err.Reason)
+}
+
 //
 // Convert the given value represented as `interface{}`
-// to a `int64` value. Returns the `defaultValue` is the value
-// is `nil`. Returns an `error` if the value cannot be
-// cast to a number. For non-primitive values, the `defaultValue`
-// is returned.
+// to an `int` value as `ConvertToInt64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
 //
-func ConvertToInt64(value interface{}, defaultValue int64) (int64, error) {
-	if value == nil {
+func ConvertToInt(value interface{}, defaultValue int) (int, error) {
+	return convertToSigned(value, defaultValue, strconv.IntSize, "int", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int` value as `ConvertToInt64WithOptions` does.
+//
+func ConvertToIntWithOptions(value interface{}, defaultValue int, options *ConvertOptions) (int, error) {
+	return convertToSigned(value, defaultValue, strconv.IntSize, "int", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int8` value as `ConvertToInt64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToInt8(value interface{}, defaultValue int8) (int8, error) {
+	return convertToSigned(value, defaultValue, 8, "int8", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int8` value as `ConvertToInt64WithOptions` does.
+//
+func ConvertToInt8WithOptions(value interface{}, defaultValue int8, options *ConvertOptions) (int8, error) {
+	return convertToSigned(value, defaultValue, 8, "int8", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int16` value as `ConvertToInt64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToInt16(value interface{}, defaultValue int16) (int16, error) {
+	return convertToSigned(value, defaultValue, 16, "int16", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int16` value as `ConvertToInt64WithOptions` does.
+//
+func ConvertToInt16WithOptions(value interface{}, defaultValue int16, options *ConvertOptions) (int16, error) {
+	return convertToSigned(value, defaultValue, 16, "int16", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int32` value as `ConvertToInt64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToInt32(value interface{}, defaultValue int32) (int32, error) {
+	return convertToSigned(value, defaultValue, 32, "int32", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to an `int32` value as `ConvertToInt64WithOptions` does.
+//
+func ConvertToInt32WithOptions(value interface{}, defaultValue int32, options *ConvertOptions) (int32, error) {
+	return convertToSigned(value, defaultValue, 32, "int32", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint` value as `ConvertToUint64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToUint(value interface{}, defaultValue uint) (uint, error) {
+	return convertToUnsigned(value, defaultValue, strconv.IntSize, "uint", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint` value as `ConvertToUint64WithOptions` does.
+//
+func ConvertToUintWithOptions(value interface{}, defaultValue uint, options *ConvertOptions) (uint, error) {
+	return convertToUnsigned(value, defaultValue, strconv.IntSize, "uint", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint8` value as `ConvertToUint64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToUint8(value interface{}, defaultValue uint8) (uint8, error) {
+	return convertToUnsigned(value, defaultValue, 8, "uint8", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint8` value as `ConvertToUint64WithOptions` does.
+//
+func ConvertToUint8WithOptions(value interface{}, defaultValue uint8, options *ConvertOptions) (uint8, error) {
+	return convertToUnsigned(value, defaultValue, 8, "uint8", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint16` value as `ConvertToUint64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToUint16(value interface{}, defaultValue uint16) (uint16, error) {
+	return convertToUnsigned(value, defaultValue, 16, "uint16", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint16` value as `ConvertToUint64WithOptions` does.
+//
+func ConvertToUint16WithOptions(value interface{}, defaultValue uint16, options *ConvertOptions) (uint16, error) {
+	return convertToUnsigned(value, defaultValue, 16, "uint16", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint32` value as `ConvertToUint64` does. Values out of
+// range return a `*NumberRangeError`, and floats are truncated
+// towards zero.
+//
+func ConvertToUint32(value interface{}, defaultValue uint32) (uint32, error) {
+	return convertToUnsigned(value, defaultValue, 32, "uint32", truncateConvertOptions)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `uint32` value as `ConvertToUint64WithOptions` does.
+//
+func ConvertToUint32WithOptions(value interface{}, defaultValue uint32, options *ConvertOptions) (uint32, error) {
+	return convertToUnsigned(value, defaultValue, 32, "uint32", options)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `float32` value. Returns the `defaultValue` is the value
+// is `nil`. Returns an `error` if the value cannot be cast to
+// a number. Finite values beyond the range of `float32` return
+// a `*NumberRangeError`. `NaN` and infinities are kept as is. Pointers, named types
+// and other non-primitive values are handled as by
+// `ConvertToInt64`.
+//
+func ConvertToFloat32(value interface{}, defaultValue float32) (float32, error) {
+	return ConvertToFloat32WithOptions(value, defaultValue, nil)
+}
+
+//
+// Convert the given value represented as `interface{}`
+// to a `float32` value as `ConvertToFloat32` does, parsing
+// strings with the `NumberFormat` of the given options. Values
+// beyond the range of `float32` are clamped if they ask to
+// saturate.
+//
+func ConvertToFloat32WithOptions(value interface{}, defaultValue float32, option *ConvertOptions) (float32, error) {
+	primitive, described := describedPrimitiveOf(value)
+	if text, ok := primitive.(string); ok && option.numberFormat() != nil {
+		parsed, err := ParseNumber(text, option.numberFormat())
+		if err != nil && described {
+			return unsupportedText(defaultValue, option)
+		}
+		if err != nil {
+			return defaultValue, err
+		}
+		primitive = parsed
+	}
+
+	if text, ok := primitive.(string); ok {
+		parsed, err := strconv.ParseFloat(text, 32)
+		if errors.Is(err, strconv.ErrRange) && math.IsInf(parsed, 0) {
+			// the parser returns an infinity, check the finite equivalent
+			return checkFloat32(value, math.Copysign(math.MaxFloat64, parsed), defaultValue, option)
+		}
+		if errors.Is(err, strconv.ErrRange) {
+			// too small to represent, rounds to zero
+			return float32(parsed), nil
+		}
+		if err != nil && described {
+			return unsupportedText(defaultValue, option)
+		}
+		if err != nil {
+			return defaultValue, err
+		}
+
+		return float32(parsed), nil
+	}
+
+	number, ok := numberOf(primitive)
+	if !ok {
 		return defaultValue, nil
 	}
 
+	return checkFloat32(value, number.float(), defaultValue, option)
+}
+
+// check that the float fits in a float32
+func checkFloat32(value interface{}, number float64, defaultValue float32, options *ConvertOptions) (float32, error) {
+	if math.IsNaN(number) || math.IsInf(number, 0) || math.Abs(number) <= math.MaxFloat32 {
+		return float32(number), nil
+	}
+
+	if options.saturate() {
+		return float32(math.Copysign(math.MaxFloat32, number)), nil
+	}
+
+	reason := NumberOverflow
+	if number < 0 {
+		reason = NumberUnderflow
+	}
+
+	return defaultValue, &NumberRangeError{Value: value, Target: "float32", Reason: reason}
+}
+
+// the kind of number held by a `numberValue`
+type numberKind int
+
+const (
+	numberSigned numberKind = iota
+	numberUnsigned
+	numberFloat
+)
+
+// a primitive number widened to 64 bits without losing
+// its sign or fractional part
+type numberValue struct {
+	kind     numberKind
+	signed   int64
+	unsigned uint64
+	floating float64
+}
+
+// return the number as a float64
+func (number numberValue) float() float64 {
+	switch number.kind {
+	case numberSigned:
+		return float64(number.signed)
+	case numberUnsigned:
+		return float64(number.unsigned)
+	}
+
+	return number.floating
+}
+
+// extract the number from a primitive numeric value
+func numberOf(value interface{}) (numberValue, bool) {
 	switch v := value.(type) {
 	case int:
-		data, _ := value.(int)
-		return int64(data), nil
-
+		return numberValue{kind: numberSigned, signed: int64(v)}, true
 	case int8:
-		data, _ := value.(int8)
-		return int64(data), nil
-
+		return numberValue{kind: numberSigned, signed: int64(v)}, true
 	case int16:
-		data, _ := value.(int16)
-		return int64(data), nil
-
+		return numberValue{kind: numberSigned, signed: int64(v)}, true
 	case int32:
-		data, _ := value.(int32)
-		return int64(data), nil
-
+		return numberValue{kind: numberSigned, signed: int64(v)}, true
 	case int64:
-		data, _ := value.(int64)
-		return int64(data), nil
-
+		return numberValue{kind: numberSigned, signed: v}, true
 	case uint:
-		data, _ := value.(uint)
-		return int64(data), nil
-
+		return numberValue{kind: numberUnsigned, unsigned: uint64(v)}, true
 	case uint8:
-		data, _ := value.(uint8)
-		return int64(data), nil
-
+		return numberValue{kind: numberUnsigned, unsigned: uint64(v)}, true
 	case uint16:
-		data, _ := value.(uint16)
-		return int64(data), nil
-
+		return numberValue{kind: numberUnsigned, unsigned: uint64(v)}, true
 	case uint32:
-		data, _ := value.(uint32)
-		return int64(data), nil
-
+		return numberValue{kind: numberUnsigned, unsigned: uint64(v)}, true
 	case uint64:
-		data, _ := value.(uint64)
-		return int64(data), nil
-
+		return numberValue{kind: numberUnsigned, unsigned: v}, true
 	case float32:
-		data, _ := value.(float32)
-		return int64(data), nil
-
+		return numberValue{kind: numberFloat, floating: float64(v)}, true
 	case float64:
-		data, _ := value.(float64)
-		return int64(data), nil
+		return numberValue{kind: numberFloat, floating: v}, true
+	}
 
-	case string:
-		return strconv.ParseInt(v, 10, 64)
+	return numberValue{}, false
+}
+
+// the signed integer types
+type signedInteger interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// the unsigned integer types
+type unsignedInteger interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// convert the value to a signed integer of the given size
+func convertToSigned[T signedInteger](value interface{}, defaultValue T, bits int, target string, options *ConvertOptions) (T, error) {
+	maximum := int64(math.MaxInt64 >> (64 - bits))
+	minimum := -maximum - 1
+
+	fail := func(reason NumberRangeReason) (T, error) {
+		if options.saturate() {
+			switch reason {
+			case NumberOverflow:
+				return T(maximum), nil
+			case NumberUnderflow:
+				return T(minimum), nil
+			}
+		}
+
+		return defaultValue, &NumberRangeError{Value: value, Target: target, Reason: reason}
 	}
 
-	// any non-nil struct is considered true
-	return defaultValue, nil
+	// strings parsed with a number format must be exact
+	fromText := false
+
+	primitive, described := describedPrimitiveOf(value)
+	if text, ok := primitive.(string); ok && options.numberFormat() != nil {
+		parsed, err := ParseNumber(text, options.numberFormat())
+		if err != nil && described {
+			return unsupportedText(defaultValue, options)
+		}
+		if err != nil {
+			return defaultValue, err
+		}
+		primitive, fromText = parsed, true
+	}
+
+	if text, ok := primitive.(string); ok {
+		parsed, err := strconv.ParseInt(text, 10, bits)
+		if errors.Is(err, strconv.ErrRange) {
+			if parsed < 0 {
+				return fail(NumberUnderflow)
+			}
+			return fail(NumberOverflow)
+		}
+		if err != nil && described {
+			return unsupportedText(defaultValue, options)
+		}
+		if err != nil {
+			return defaultValue, err
+		}
+
+		return T(parsed), nil
+	}
+
+	number, ok := numberOf(primitive)
+	if !ok {
+		return defaultValue, nil
+	}
+
+	switch number.kind {
+	case numberSigned:
+		if number.signed < minimum {
+			return fail(NumberUnderflow)
+		}
+		if number.signed > maximum {
+			return fail(NumberOverflow)
+		}
+		return T(number.signed), nil
+
+	case numberUnsigned:
+		if number.unsigned > uint64(maximum) {
+			return fail(NumberOverflow)
+		}
+		return T(number.unsigned), nil
+	}
+
+	if math.IsNaN(number.floating) {
+		return fail(NumberNaN)
+	}
+
+	truncated := math.Trunc(number.floating)
+	if truncated < float64(minimum) {
+		return fail(NumberUnderflow)
+	}
+	if truncated >= -float64(minimum) {
+		return fail(NumberOverflow)
+	}
+	if truncated != number.floating && (!options.truncate() || fromText) {
+		return fail(NumberFraction)
+	}
+
+	return T(truncated), nil
+}
+
+// convert the value to an unsigned integer of the given size
+func convertToUnsigned[T unsignedInteger](value interface{}, defaultValue T, bits int, target string, options *ConvertOptions) (T, error) {
+	maximum := uint64(math.MaxUint64 >> (64 - bits))
+
+	fail := func(reason NumberRangeReason) (T, error) {
+		if options.saturate() {
+			switch reason {
+			case NumberOverflow:
+				return T(maximum), nil
+			case NumberNegative:
+				return 0, nil
+			}
+		}
+
+		return defaultValue, &NumberRangeError{Value: value, Target: target, Reason: reason}
+	}
+
+	// strings parsed with a number format must be exact
+	fromText := false
+
+	primitive, described := describedPrimitiveOf(value)
+	if text, ok := primitive.(string); ok && options.numberFormat() != nil {
+		parsed, err := ParseNumber(text, options.numberFormat())
+		if err != nil && described {
+			return unsupportedText(defaultValue, options)
+		}
+		if err != nil {
+			return defaultValue, err
+		}
+		primitive, fromText = parsed, true
+	}
+
+	if text, ok := primitive.(string); ok {
+		parsed, err := strconv.ParseUint(text, 10, bits)
+		if errors.Is(err, strconv.ErrRange) {
+			return fail(NumberOverflow)
+		}
+		if err != nil {
+			// report negative numbers rather than a syntax error
+			if strings.HasPrefix(text, "-") {
+				if _, signedErr := strconv.ParseInt(text, 10, 64); signedErr == nil || errors.Is(signedErr, strconv.ErrRange) {
+					return fail(NumberNegative)
+				}
+			}
+			if described {
+				return unsupportedText(defaultValue, options)
+			}
+			return defaultValue, err
+		}
+
+		return T(parsed), nil
+	}
+
+	number, ok := numberOf(primitive)
+	if !ok {
+		return defaultValue, nil
+	}
+
+	switch number.kind {
+	case numberSigned:
+		if number.signed < 0 {
+			return fail(NumberNegative)
+		}
+		if uint64(number.signed) > maximum {
+			return fail(NumberOverflow)
+		}
+		return T(number.signed), nil
+
+	case numberUnsigned:
+		if number.unsigned > maximum {
+			return fail(NumberOverflow)
+		}
+		return T(number.unsigned), nil
+	}
+
+	if math.IsNaN(number.floating) {
+		return fail(NumberNaN)
+	}
+
+	truncated := math.Trunc(number.floating)
+	if truncated < 0 {
+		return fail(NumberNegative)
+	}
+	if truncated >= math.Ldexp(1, bits) {
+		return fail(NumberOverflow)
+	}
+	if truncated != number.floating && (!options.truncate() || fromText) {
+		return fail(NumberFraction)
+	}
+
+	return T(truncated), nil
 }
 
 //
-// Convert the given value represented as `interface{}`
-// to a `float64` value. Returns the `defaultValue` is the value
-// is `nil`. Returns an `error` if the value cannot be
-// cast to a number. For non-primitive values, the `defaultValue`
-// is returned.
+// Returned by `Convert` when a value is not supported by the
+// conversion functions, such as a struct converted to a number.
 //
-func ConvertToFloat64(value interface{}, defaultValue float64) (float64, error) {
+var ErrUnsupportedConversion = errors.New("Unsupported conversion")
+
+//
+// Returned by `Convert` and `ConvertOr` when a value cannot be
+// converted to the target type. The cause is one of the errors
+// returned by the `ConvertTo*` functions, like a
+// `*NumberRangeError` or a `*strconv.NumError`, and can be
+// inspected with `errors.Is` and `errors.As`.
+//
+type ConversionError struct {
+	// the value that was being converted
+	Value interface{}
+
+	// the type of the value, `nil` if the value is `nil`
+	SourceType reflect.Type
+
+	// the type the value was being converted to
+	TargetType reflect.Type
+
+	// the underlying error
+	Cause error
+}
+
+func (err *ConversionError) Error() string {
+	cause := err.Cause.Error()
+
+	var rangeErr *NumberRangeError
+	var numErr *strconv.NumError
+	if errors.As(err.Cause, &rangeErr) {
+		cause = rangeErr.Reason.String()
+	} else if errors.As(err.Cause, &numErr) {
+		cause = numErr.Err.Error()
+	}
+
+	return fmt.Sprintf("Cannot convert %v %#v to %v: %s", err.SourceType, err.Value, err.TargetType, cause)
+}
+
+func (err *ConversionError) Unwrap() error {
+	return err.Cause
+}
+
+//
+// Convert the given value represented as `interface{}` to the
+// type `T` using the matching `ConvertTo*` function. `T` may be
+// any boolean, string, integer, float or interface type,
+// including named types such as `type Port int`, one of
+// `time.Time` and `time.Duration`, or a slice or map of these
+// as by `ConvertToSlice` and `ConvertToMap`. Returns the
+// zero value if the value is `nil`. Returns a `*ConversionError`
+// if the value cannot be converted; unlike the `ConvertTo*`
+// functions, non-primitive values are reported with the cause
+// `ErrUnsupportedConversion` rather than ignored.
+//
+func Convert[T any](value interface{}) (T, error) {
+	return ConvertWithOptions[T](value, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to the
+// type `T` as `Convert` does, using the given options.
+//
+func ConvertWithOptions[T any](value interface{}, options *ConvertOptions) (T, error) {
+	if converted, ok := value.(T); ok {
+		return converted, nil
+	}
+
+	var result T
+	if isNilValue(value) {
+		return result, nil
+	}
+
+	target := reflect.TypeOf(&result).Elem()
+	converted, err := convertToType(value, target, options)
+	if err != nil {
+		return result, &ConversionError{
+			Value:      value,
+			SourceType: reflect.TypeOf(value),
+			TargetType: target,
+			Cause:      err,
+		}
+	}
+
+	reflect.ValueOf(&result).Elem().Set(converted)
+	return result, nil
+}
+
+//
+// Convert the given value represented as `interface{}` to the
+// type `T` as `Convert` does. Returns the `defaultValue` if the
+// value is `nil` or cannot be converted.
+//
+func ConvertOr[T any](value interface{}, defaultValue T) T {
+	return ConvertOrWithOptions(value, defaultValue, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to the
+// type `T` as `ConvertWithOptions` does. Returns the
+// `defaultValue` if the value is `nil` or cannot be converted.
+//
+func ConvertOrWithOptions[T any](value interface{}, defaultValue T, options *ConvertOptions) T {
+	if isNilValue(value) {
+		return defaultValue
+	}
+
+	converted, err := ConvertWithOptions[T](value, options)
+	if err != nil {
+		return defaultValue
+	}
+
+	return converted
+}
+
+// check if the value can be read as a number
+func isConvertibleNumber(value interface{}) bool {
+	primitive := primitiveOf(value)
+	if _, ok := primitive.(string); ok {
+		return true
+	}
+
+	_, ok := numberOf(primitive)
+	return ok
+}
+
+// types converted by their own `ConvertTo*` function
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convert the non-nil value to the target type using the
+// `ConvertTo*` function for its kind
+func convertToType(value interface{}, target reflect.Type, options *ConvertOptions) (reflect.Value, error) {
+	result := reflect.New(target).Elem()
+
+	// numbers are only read from text that is a number
+	rejecting := &ConvertOptions{}
+	if options != nil {
+		*rejecting = *options
+	}
+	rejecting.RejectUnsupported = true
+
+	switch target {
+	case timeType:
+		converted, err := convertToTime(value, time.Time{}, options)
+		result.Set(reflect.ValueOf(converted))
+		return result, err
+
+	case durationType:
+		converted, err := convertToDuration(value, 0, options)
+		result.SetInt(int64(converted))
+		return result, err
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		converted, err := ConvertToBoolWithOptions(value, options)
+		result.SetBool(converted)
+		return result, err
+
+	case reflect.String:
+		result.SetString(ConvertToString(value))
+		return result, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isConvertibleNumber(value) {
+			return result, ErrUnsupportedConversion
+		}
+
+		converted, err := convertToSigned[int64](value, 0, target.Bits(), target.String(), rejecting)
+		result.SetInt(converted)
+		return result, err
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !isConvertibleNumber(value) {
+			return result, ErrUnsupportedConversion
+		}
+
+		converted, err := convertToUnsigned[uint64](value, 0, target.Bits(), target.String(), rejecting)
+		result.SetUint(converted)
+		return result, err
+
+	case reflect.Float32:
+		if !isConvertibleNumber(value) {
+			return result, ErrUnsupportedConversion
+		}
+
+		converted, err := ConvertToFloat32WithOptions(value, 0, rejecting)
+		result.SetFloat(float64(converted))
+		return result, err
+
+	case reflect.Float64:
+		if !isConvertibleNumber(value) {
+			return result, ErrUnsupportedConversion
+		}
+
+		converted, err := ConvertToFloat64WithOptions(value, 0, rejecting)
+		result.SetFloat(converted)
+		return result, err
+
+	case reflect.Slice:
+		if target.Elem().Kind() == reflect.Uint8 {
+			if text, ok := primitiveOf(value).(string); ok {
+				return reflect.ValueOf([]byte(text)).Convert(target), nil
+			}
+		}
+
+		return convertToSliceValue(value, target, options)
+
+	case reflect.Map:
+		return convertToMapValue(value, target, options)
+
+	case reflect.Interface:
+		if reflect.TypeOf(value).Implements(target) {
+			result.Set(reflect.ValueOf(value))
+			return result, nil
+		}
+	}
+
+	return result, ErrUnsupportedConversion
+}
+
+// check if the value is `nil` or a `nil` pointer
+func isNilValue(value interface{}) bool {
 	if value == nil {
-		return defaultValue, nil
+		return true
 	}
 
+	reflected := reflect.ValueOf(value)
+	for reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return true
+		}
+		reflected = reflected.Elem()
+	}
+
+	return false
+}
+
+// reduce the value to a built-in `bool`, `string`, `int64`,
+// `uint64` or `float64` where possible. Pointers are followed,
+// named types are reduced to their kind, `[]byte` and
+// `json.Number` become strings, `big.Int` and `big.Float`
+// become the closest number, and values implementing
+// `encoding.TextMarshaler` or `fmt.Stringer` become strings.
+// Returns `nil` for `nil` pointers, and other values as is.
+func primitiveOf(value interface{}) interface{} {
+	primitive, _ := describedPrimitiveOf(value)
+	return primitive
+}
+
+// reduce the value as `primitiveOf` does, also returning `true`
+// if it became a string through `encoding.TextMarshaler` or
+// `fmt.Stringer`
+func describedPrimitiveOf(value interface{}) (interface{}, bool) {
 	switch v := value.(type) {
-	case int:
-		data, _ := value.(int)
-		return float64(data), nil
+	case nil, bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, false
 
-	case int8:
-		data, _ := value.(int8)
-		return float64(data), nil
+	case json.Number:
+		return string(v), false
 
-	case int16:
-		data, _ := value.(int16)
-		return float64(data), nil
+	case []byte:
+		return string(v), false
 
-	case int32:
-		data, _ := value.(int32)
-		return float64(data), nil
+	case big.Int:
+		return bigIntValue(&v), false
 
-	case int64:
-		data, _ := value.(int64)
-		return float64(data), nil
+	case *big.Int:
+		if v == nil {
+			return nil, false
+		}
+		return bigIntValue(v), false
 
-	case uint:
-		data, _ := value.(uint)
-		return float64(data), nil
+	case big.Float:
+		return bigFloatValue(&v), false
 
-	case uint8:
-		data, _ := value.(uint8)
-		return float64(data), nil
+	case *big.Float:
+		if v == nil {
+			return nil, false
+		}
+		return bigFloatValue(v), false
+	}
 
-	case uint16:
-		data, _ := value.(uint16)
-		return float64(data), nil
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Ptr:
+		if reflected.IsNil() {
+			return nil, false
+		}
 
-	case uint32:
-		data, _ := value.(uint32)
-		return float64(data), nil
+		// methods with a pointer receiver are only seen
+		// on the pointer itself
+		if pointed, described := describedPrimitiveOf(reflected.Elem().Interface()); isPrimitive(pointed) {
+			return pointed, described
+		}
 
-	case uint64:
-		data, _ := value.(uint64)
-		return float64(data), nil
+	case reflect.Bool:
+		return reflected.Bool(), false
 
-	case float32:
-		data, _ := value.(float32)
-		return float64(data), nil
+	case reflect.String:
+		return reflected.String(), false
 
-	case float64:
-		data, _ := value.(float64)
-		return float64(data), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int(), false
 
-	case string:
-		return strconv.ParseFloat(v, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflected.Uint(), false
+
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), false
+
+	case reflect.Slice:
+		if reflected.Type().Elem().Kind() == reflect.Uint8 {
+			return string(reflected.Bytes()), false
+		}
+	}
+
+	switch v := value.(type) {
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return string(text), true
+		}
+
+	case fmt.Stringer:
+		return v.String(), true
+	}
+
+	return value, false
+}
+
+// the result of a number conversion of a value whose text, from
+// `encoding.TextMarshaler` or `fmt.Stringer`, is not a number:
+// the `defaultValue`, as for other non-primitive values, or an
+// `ErrUnsupportedConversion` if the options ask to
+// `RejectUnsupported`
+func unsupportedText[T any](defaultValue T, options *ConvertOptions) (T, error) {
+	if options.rejectUnsupported() {
+		return defaultValue, ErrUnsupportedConversion
 	}
 
-	// any non-nil struct is considered true
 	return defaultValue, nil
 }
+
+// check if the value is of a built-in primitive type
+func isPrimitive(value interface{}) bool {
+	switch value.(type) {
+	case bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+
+	return false
+}
+
+// return the big integer as an `int64` or `uint64` if it fits,
+// or the nearest `float64` otherwise so that range checks fail
+func bigIntValue(number *big.Int) interface{} {
+	if number.IsInt64() {
+		return number.Int64()
+	}
+
+	if number.IsUint64() {
+		return number.Uint64()
+	}
+
+	converted, _ := new(big.Float).SetInt(number).Float64()
+	return converted
+}
+
+// return the big float as an `int64` or `uint64` if it is an
+// integer that fits, or the nearest `float64` otherwise
+func bigFloatValue(number *big.Float) interface{} {
+	if number.IsInt() {
+		if converted, accuracy := number.Int64(); accuracy == big.Exact {
+			return converted
+		}
+
+		if converted, accuracy := number.Uint64(); accuracy == big.Exact {
+			return converted
+		}
+	}
+
+	converted, _ := number.Float64()
+	return converted
+}
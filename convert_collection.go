@@ -0,0 +1,239 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//
+// Convert the given value represented as `interface{}` to a
+// slice of `T`, converting each element as `Convert` does.
+// Accepts any slice or array, see `IsSlice`, a string of
+// elements delimited by the `Separator` of the options like
+// `a,b,c` with whitespace around each element removed, or a
+// single value that becomes the only element. Returns `nil` if
+// the value is `nil`, and an empty slice for an empty string.
+// Returns a `*FieldError` whose path is the index of the first
+// element that cannot be converted, like `[2]`, or a
+// `*ConversionError` for maps and structs.
+//
+func ConvertToSlice[T any](value interface{}) ([]T, error) {
+	return ConvertToSliceWithOptions[T](value, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// slice of `T` as `ConvertToSlice` does, using the given options.
+//
+func ConvertToSliceWithOptions[T any](value interface{}, options *ConvertOptions) ([]T, error) {
+	if converted, ok := value.([]T); ok {
+		return converted, nil
+	}
+
+	if isNilValue(value) {
+		return nil, nil
+	}
+
+	target := reflect.TypeOf([]T(nil))
+	converted, err := convertToSliceValue(value, target, options)
+	if err == ErrUnsupportedConversion {
+		return nil, &ConversionError{
+			Value:      value,
+			SourceType: reflect.TypeOf(value),
+			TargetType: target,
+			Cause:      err,
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return converted.Interface().([]T), nil
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// map from `K` to `V`, converting each key and value as
+// `Convert` does. Accepts any map, see `IsMap`. Returns `nil`
+// if the value is `nil`. Returns a `*FieldError` whose path is
+// the first key, in sorted order, whose key or value cannot be
+// converted, or a `*ConversionError` if the value is not a map.
+//
+func ConvertToMap[K comparable, V any](value interface{}) (map[K]V, error) {
+	return ConvertToMapWithOptions[K, V](value, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// map from `K` to `V` as `ConvertToMap` does, using the given
+// options.
+//
+func ConvertToMapWithOptions[K comparable, V any](value interface{}, options *ConvertOptions) (map[K]V, error) {
+	if converted, ok := value.(map[K]V); ok {
+		return converted, nil
+	}
+
+	if isNilValue(value) {
+		return nil, nil
+	}
+
+	target := reflect.TypeOf(map[K]V(nil))
+	converted, err := convertToMapValue(value, target, options)
+	if err == ErrUnsupportedConversion {
+		return nil, &ConversionError{
+			Value:      value,
+			SourceType: reflect.TypeOf(value),
+			TargetType: target,
+			Cause:      err,
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return converted.Interface().(map[K]V), nil
+}
+
+// convert the elements of the value to a slice of the target
+// type, returning `ErrUnsupportedConversion` if the value has
+// no elements
+func convertToSliceValue(value interface{}, target reflect.Type, options *ConvertOptions) (reflect.Value, error) {
+	items, ok := sliceItems(value, options.separator())
+	if !ok {
+		return reflect.New(target).Elem(), ErrUnsupportedConversion
+	}
+
+	result := reflect.MakeSlice(target, len(items), len(items))
+	for index, item := range items {
+		if err := convertElement(item, result.Index(index), options); err != nil {
+			return reflect.New(target).Elem(), elementError("["+strconv.Itoa(index)+"]", item, target.Elem(), err)
+		}
+	}
+
+	return result, nil
+}
+
+// convert the keys and values of the map to a map of the target
+// type, returning `ErrUnsupportedConversion` if the value is not
+// a map
+func convertToMapValue(value interface{}, target reflect.Type, options *ConvertOptions) (reflect.Value, error) {
+	if !IsMap(value) {
+		return reflect.New(target).Elem(), ErrUnsupportedConversion
+	}
+
+	source := reflect.ValueOf(value)
+	result := reflect.MakeMapWithSize(target, source.Len())
+	for _, key := range sortedMapKeys(source) {
+		path := joinFieldPath("", ConvertToString(key.Interface()))
+
+		mapKey := reflect.New(target.Key()).Elem()
+		if err := convertElement(key.Interface(), mapKey, options); err != nil {
+			return reflect.New(target).Elem(), elementError(path, key.Interface(), target.Key(), err)
+		}
+
+		item := source.MapIndex(key).Interface()
+		mapValue := reflect.New(target.Elem()).Elem()
+		if err := convertElement(item, mapValue, options); err != nil {
+			return reflect.New(target).Elem(), elementError(path, item, target.Elem(), err)
+		}
+
+		result.SetMapIndex(mapKey, mapValue)
+	}
+
+	return result, nil
+}
+
+// convert the element into the target, leaving the zero value
+// for `nil`
+func convertElement(item interface{}, target reflect.Value, options *ConvertOptions) error {
+	if isNilValue(item) {
+		return nil
+	}
+
+	if reflect.TypeOf(item).AssignableTo(target.Type()) {
+		target.Set(reflect.ValueOf(item))
+		return nil
+	}
+
+	converted, err := convertToType(item, target.Type(), options)
+	if err != nil {
+		return err
+	}
+
+	target.Set(converted)
+	return nil
+}
+
+// return the error for the element at the path, extending the
+// path of errors from nested slices and maps
+func elementError(path string, item interface{}, target reflect.Type, err error) error {
+	if nested, ok := err.(*FieldError); ok {
+		if strings.HasPrefix(nested.Path, "[") {
+			return &FieldError{Path: path + nested.Path, Err: nested.Err}
+		}
+		return &FieldError{Path: path + "." + nested.Path, Err: nested.Err}
+	}
+
+	return &FieldError{
+		Path: path,
+		Err: &ConversionError{
+			Value:      item,
+			SourceType: reflect.TypeOf(item),
+			TargetType: target,
+			Cause:      err,
+		},
+	}
+}
+
+// return the elements of a slice or array, the elements of a
+// string delimited by the separator, the value itself for a
+// single value or when the separator is empty, or `false` for
+// a map or struct
+func sliceItems(value interface{}, separator string) ([]interface{}, bool) {
+	reflected := reflect.ValueOf(value)
+	if IsSlice(value) || reflected.Kind() == reflect.Array {
+		items := make([]interface{}, reflected.Len())
+		for index := range items {
+			items[index] = reflected.Index(index).Interface()
+		}
+		return items, true
+	}
+
+	if IsMap(value) {
+		return nil, false
+	}
+
+	primitive := primitiveOf(value)
+	if text, ok := primitive.(string); ok && separator != "" {
+		if strings.TrimSpace(text) == "" {
+			return []interface{}{}, true
+		}
+
+		parts := strings.Split(text, separator)
+		items := make([]interface{}, len(parts))
+		for index, part := range parts {
+			items[index] = strings.TrimSpace(part)
+		}
+		return items, true
+	}
+
+	if !isPrimitive(primitive) {
+		return nil, false
+	}
+
+	return []interface{}{value}, true
+}
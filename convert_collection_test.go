@@ -0,0 +1,176 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToSlice(t *testing.T) {
+	ints, err := ConvertToSlice[int]([]interface{}{1, "2", 3.0, nil})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 0}, ints)
+
+	ints, err = ConvertToSlice[int]([3]string{"4", "5", "6"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6}, ints)
+
+	ints, err = ConvertToSlice[int]("1, 2 ,3")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ints)
+
+	ints, err = ConvertToSlice[int](42)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{42}, ints)
+
+	ints, err = ConvertToSlice[int]("")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{}, ints)
+
+	ints, err = ConvertToSlice[int](nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ints)
+
+	// the same slice is returned
+	original := []string{"a"}
+	strings, err := ConvertToSlice[string](original)
+	assert.NoError(t, err)
+	assert.Equal(t, original, strings)
+
+	strings, err = ConvertToSliceWithOptions[string]("a|b", &ConvertOptions{Separator: "|"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, strings)
+
+	bools, err := ConvertToSlice[bool]([]string{"yes", "off"})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, bools)
+
+	durations, err := ConvertToSlice[time.Duration]("1s,2m")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, durations)
+
+	// nested slices
+	nested, err := ConvertToSlice[[]int]([]interface{}{"1,2", []string{"3"}})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3}}, nested)
+
+	// options are applied to the elements
+	ints, err = ConvertToSliceWithOptions[int]([]string{"0x10", "1_000"}, &ConvertOptions{NumberFormat: LenientNumberFormat()})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{16, 1000}, ints)
+
+	// generic conversion splits strings like `ConvertToSlice`,
+	// where it used to report them as unsupported
+	generic, err := Convert[[]int]("1,2")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, generic)
+}
+
+func TestConvertToSliceErrors(t *testing.T) {
+	_, err := ConvertToSlice[int]([]string{"1", "2", "x"})
+
+	var fieldErr *FieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[2]", fieldErr.Path)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Equal(t, `Field [2]: Cannot convert string "x" to int: invalid syntax`, err.Error())
+
+	_, err = ConvertToSlice[int8]("1,300")
+	var rangeErr *NumberRangeError
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[1]", fieldErr.Path)
+
+	_, err = ConvertToSlice[[]int]([]interface{}{"1", []string{"2", "x"}})
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[1][1]", fieldErr.Path)
+
+	_, err = ConvertToSlice[map[string]int]([]interface{}{map[string]string{"a": "x"}})
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[0].a", fieldErr.Path)
+
+	// not a slice
+	var conversionErr *ConversionError
+	_, err = ConvertToSlice[int](map[string]int{"a": 1})
+	assert.ErrorAs(t, err, &conversionErr)
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = ConvertToSlice[int](struct{}{})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+}
+
+func TestConvertToMap(t *testing.T) {
+	converted, err := ConvertToMap[string, int](map[string]interface{}{"a": "1", "b": 2.0, "c": nil})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 0}, converted)
+
+	keys, err := ConvertToMap[int, bool](map[string]string{"1": "true", "2": "no"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true, 2: false}, keys)
+
+	lists, err := ConvertToMap[string, []int](map[string]interface{}{"a": "1,2", "b": []float64{3}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"a": {1, 2}, "b": {3}}, lists)
+
+	original := map[string]int{"a": 1}
+	same, err := ConvertToMap[string, int](original)
+	assert.NoError(t, err)
+	assert.Equal(t, original, same)
+
+	missing, err := ConvertToMap[string, int](nil)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// generic conversion
+	generic, err := Convert[map[string]float64](map[interface{}]interface{}{"x": "1.5"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"x": 1.5}, generic)
+}
+
+func TestConvertToMapErrors(t *testing.T) {
+	var fieldErr *FieldError
+
+	_, err := ConvertToMap[string, int](map[string]interface{}{"a": "1", "b": "x", "c": "y"})
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "b", fieldErr.Path)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	_, err = ConvertToMap[int, string](map[string]string{"1": "a", "two": "b"})
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "two", fieldErr.Path)
+
+	_, err = ConvertToMap[string, []int](map[string]interface{}{"a.b": []string{"1", "x"}})
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, `["a.b"][1]`, fieldErr.Path)
+
+	// the path locates the value
+	data := map[string]interface{}{"list": []interface{}{1, "x"}}
+	_, err = ConvertToMap[string, []int](data)
+	assert.ErrorAs(t, err, &fieldErr)
+	value, pathErr := GetPath(data, fieldErr.Path)
+	assert.NoError(t, pathErr)
+	assert.Equal(t, "x", value)
+
+	// not a map
+	_, err = ConvertToMap[string, int]([]int{1})
+	var conversionErr *ConversionError
+	assert.True(t, errors.As(err, &conversionErr))
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = ConvertToMap[string, int]("a=1")
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+}
@@ -12,8 +12,15 @@
 package berry
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -387,3 +394,698 @@ func TestConvertToFloat64(t *testing.T) {
 	assert.Equal(t, float64(59), value)
 	assert.NoError(t, err)
 }
+
+func TestConvertToIntegerRange(t *testing.T) {
+	var rangeErr *NumberRangeError
+
+	// values that fit
+	i8, err := ConvertToInt8(int64(-128), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-128), i8)
+
+	u8, err := ConvertToUint8(255.0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(255), u8)
+
+	i, err := ConvertToInt("-42", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, -42, i)
+
+	u, err := ConvertToUint(uint64(7), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), u)
+
+	// overflow and underflow
+	i8, err = ConvertToInt8(300, 5)
+	assert.Equal(t, int8(5), i8)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+	assert.Equal(t, "int8", rangeErr.Target)
+	assert.Equal(t, 300, rangeErr.Value)
+	assert.Equal(t, "Cannot convert 300 to int8: value overflows", err.Error())
+
+	_, err = ConvertToInt16(int32(-40000), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberUnderflow, rangeErr.Reason)
+
+	_, err = ConvertToInt32(uint64(1<<40), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToInt64(uint64(math.MaxUint64), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+
+	_, err = ConvertToInt64(1e20, 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToUint16("70000", 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToInt8("-129", 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberUnderflow, rangeErr.Reason)
+
+	// negative to unsigned
+	_, err = ConvertToUint64(-1, 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNegative, rangeErr.Reason)
+
+	_, err = ConvertToUint32(-3.5, 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNegative, rangeErr.Reason)
+
+	_, err = ConvertToUint8("-5", 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNegative, rangeErr.Reason)
+
+	_, err = ConvertToUint8("abc", 0)
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &rangeErr))
+
+	// fractions are reported with options
+	_, err = ConvertToUint8WithOptions(255.9, 0, nil)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	_, err = ConvertToInt64WithOptions(-0.5, 0, nil)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	// NaN and infinity
+	_, err = ConvertToInt(math.NaN(), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNaN, rangeErr.Reason)
+
+	_, err = ConvertToUint(math.Inf(1), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToInt32(float32(math.Inf(-1)), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberUnderflow, rangeErr.Reason)
+}
+
+func TestConvertToIntegerOptions(t *testing.T) {
+	saturate := &ConvertOptions{Saturate: true}
+	truncate := &ConvertOptions{Truncate: true}
+
+	i8, err := ConvertToInt8WithOptions(1000, 0, saturate)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(127), i8)
+
+	i8, err = ConvertToInt8WithOptions("-1000", 0, saturate)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-128), i8)
+
+	i64, err := ConvertToInt64WithOptions(math.Inf(1), 0, saturate)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64), i64)
+
+	u16, err := ConvertToUint16WithOptions(-5, 0, saturate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), u16)
+
+	u64, err := ConvertToUint64WithOptions(1e30, 0, saturate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), u64)
+
+	// NaN cannot be clamped
+	_, err = ConvertToIntWithOptions(math.NaN(), 0, saturate)
+	assert.Error(t, err)
+
+	// fractional truncation
+	var rangeErr *NumberRangeError
+	_, err = ConvertToIntWithOptions(32.8, 0, nil)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	i, err := ConvertToIntWithOptions(32.8, 0, truncate)
+	assert.NoError(t, err)
+	assert.Equal(t, 32, i)
+
+	u, err := ConvertToUint(32.0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(32), u)
+
+	_, err = ConvertToUintWithOptions(float32(0.25), 0, nil)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	// small negative fractions truncate to zero
+	u8, err := ConvertToUint8WithOptions(-0.5, 1, truncate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), u8)
+
+	u64, err = ConvertToUint64WithOptions(32.8, 0, truncate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(32), u64)
+
+	// the conversions without options truncate at every width
+	i64, err = ConvertToInt64(-32.8, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-32), i64)
+
+	i8, err = ConvertToInt8(32.8, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(32), i8)
+
+	i, err = ConvertToInt(-32.8, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, -32, i)
+
+	u16, err = ConvertToUint16(255.9, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(255), u16)
+
+	u, err = ConvertToUint(float32(0.25), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), u)
+
+	var convertSmall func(interface{}, int16) (int16, error) = ConvertToInt16
+	i16, err := convertSmall(12.5, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int16(12), i16)
+
+	var convert func(interface{}, uint64) (uint64, error) = ConvertToUint64
+	u64, err = convert(12.5, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), u64)
+
+	// nil options use the defaults
+	_, err = ConvertToInt8WithOptions(1000, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestConvertToFloat32(t *testing.T) {
+	value, err := ConvertToFloat32(int(32), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(32), value)
+
+	value, err = ConvertToFloat32("32.5", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(32.5), value)
+
+	value, err = ConvertToFloat32(strings.Builder{}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3), value)
+
+	value, err = ConvertToFloat32(nil, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3), value)
+
+	_, err = ConvertToFloat32("abc", 0)
+	assert.Error(t, err)
+
+	// infinities and NaN are representable
+	value, err = ConvertToFloat32(math.Inf(-1), 0)
+	assert.NoError(t, err)
+	assert.True(t, math.IsInf(float64(value), -1))
+
+	value, err = ConvertToFloat32(math.NaN(), 0)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(float64(value)))
+
+	// finite values out of range
+	var rangeErr *NumberRangeError
+	_, err = ConvertToFloat32(1e300, 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToFloat32("-1e300", 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberUnderflow, rangeErr.Reason)
+
+	value, err = ConvertToFloat32WithOptions(-1e300, 0, &ConvertOptions{Saturate: true})
+	assert.NoError(t, err)
+	assert.Equal(t, float32(-math.MaxFloat32), value)
+}
+
+func TestConvert(t *testing.T) {
+	i, err := Convert[int]("42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, i)
+
+	i8, err := Convert[int8](12.0)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(12), i8)
+
+	u16, err := Convert[uint16](int64(65535))
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(65535), u16)
+
+	f32, err := Convert[float32]("1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(1.5), f32)
+
+	f64, err := Convert[float64](uint8(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, f64)
+
+	b, err := Convert[bool]("true")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	s, err := Convert[string](12)
+	assert.NoError(t, err)
+	assert.Equal(t, "12", s)
+
+	// values of the target type are returned as is
+	builder, err := Convert[*strings.Builder](&strings.Builder{})
+	assert.NoError(t, err)
+	assert.NotNil(t, builder)
+
+	anything, err := Convert[interface{}](7)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, anything)
+
+	asError, err := Convert[error](errors.New("boom"))
+	assert.NoError(t, err)
+	assert.Equal(t, "boom", asError.Error())
+
+	// named target types
+	type Port uint16
+	type Level string
+	port, err := Convert[Port]("8080")
+	assert.NoError(t, err)
+	assert.Equal(t, Port(8080), port)
+
+	level, err := Convert[Level](3)
+	assert.NoError(t, err)
+	assert.Equal(t, Level("3"), level)
+
+	// nil returns the zero value
+	i, err = Convert[int](nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, i)
+
+	// options are passed along
+	i8, err = ConvertWithOptions[int8](1000, &ConvertOptions{Saturate: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int8(127), i8)
+}
+
+func TestConvertErrors(t *testing.T) {
+	var conversionErr *ConversionError
+	var rangeErr *NumberRangeError
+
+	_, err := Convert[int]("abc")
+	assert.ErrorAs(t, err, &conversionErr)
+	assert.Equal(t, "abc", conversionErr.Value)
+	assert.Equal(t, reflect.TypeOf(""), conversionErr.SourceType)
+	assert.Equal(t, reflect.TypeOf(0), conversionErr.TargetType)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Equal(t, `Cannot convert string "abc" to int: invalid syntax`, err.Error())
+
+	type Port uint16
+	_, err = Convert[Port](70000)
+	assert.ErrorAs(t, err, &conversionErr)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+	assert.Equal(t, "berry.Port", rangeErr.Target)
+	assert.Equal(t, "Cannot convert int 70000 to berry.Port: value overflows", err.Error())
+
+	_, err = Convert[int](strings.Builder{})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = Convert[float64](true)
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = Convert[error](12)
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = Convert[[]int](map[string]int{"a": 1})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+}
+
+func TestConvertOr(t *testing.T) {
+	assert.Equal(t, 42, ConvertOr("42", 7))
+	assert.Equal(t, 7, ConvertOr("abc", 7))
+	assert.Equal(t, 7, ConvertOr(nil, 7))
+	assert.Equal(t, uint8(5), ConvertOr[uint8](-1, 5))
+	assert.Equal(t, uint8(0), ConvertOrWithOptions[uint8](-1, 5, &ConvertOptions{Saturate: true}))
+	assert.Equal(t, "x", ConvertOr[string](nil, "x"))
+}
+
+type testStringer struct {
+	text string
+}
+
+func (ts testStringer) String() string {
+	return ts.text
+}
+
+type testTextMarshaler struct {
+	text string
+}
+
+func (tm *testTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte(tm.text), nil
+}
+
+func TestConvertReflection(t *testing.T) {
+	type Port int
+	type Ratio float32
+	type Flag bool
+	type Name string
+
+	// named types
+	i64, err := ConvertToInt64(Port(8080), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), i64)
+
+	f64, err := ConvertToFloat64(Ratio(0.5), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, f64)
+
+	b, err := ConvertToBool(Flag(true))
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	i, err := ConvertToInt(Name("12"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, i)
+
+	_, err = ConvertToInt8(Port(8080), 0)
+	assert.Error(t, err)
+
+	// pointers
+	number := 42
+	pointer := &number
+	i64, err = ConvertToInt64(&pointer, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), i64)
+	assert.Equal(t, "42", ConvertToString(pointer))
+
+	var nilPointer *int
+	i64, err = ConvertToInt64(nilPointer, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), i64)
+	assert.Equal(t, "", ConvertToString(nilPointer))
+	b, err = ConvertToBool(nilPointer)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	// time.Duration is a named int64
+	i64, err = ConvertToInt64(time.Second, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000000000), i64)
+	assert.Equal(t, "1s", ConvertToString(time.Second))
+
+	// json.Number and bytes
+	u16, err := ConvertToUint16(json.Number("443"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(443), u16)
+
+	f64, err = ConvertToFloat64(json.Number("2.5"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, f64)
+
+	i64, err = ConvertToInt64([]byte("99"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(99), i64)
+	assert.Equal(t, "hello", ConvertToString([]byte("hello")))
+
+	// text marshalers and stringers
+	i64, err = ConvertToInt64(testStringer{"15"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), i64)
+
+	i64, err = ConvertToInt64(&testTextMarshaler{"16"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), i64)
+	assert.Equal(t, "16", ConvertToString(&testTextMarshaler{"16"}))
+
+	b, err = ConvertToBool(testStringer{"true"})
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	// text that is not a number is not supported
+	moment := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	i64, err = ConvertToInt64(moment, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), i64)
+
+	u8, err := ConvertToUint8(testStringer{"id-12"}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(3), u8)
+
+	f64, err = ConvertToFloat64(&testTextMarshaler{"n/a"}, 1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f64)
+
+	reject := &ConvertOptions{RejectUnsupported: true}
+	_, err = ConvertToInt64WithOptions(moment, 7, reject)
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = ConvertToFloat32WithOptions(testStringer{"id-12"}, 0, reject)
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	_, err = Convert[int](testStringer{"id-12"})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	// but text out of range is still reported
+	_, err = ConvertToInt8(testStringer{"300"}, 0)
+	var rangeErr *NumberRangeError
+	assert.ErrorAs(t, err, &rangeErr)
+
+	// generic conversion
+	port, err := Convert[Port](json.Number("80"))
+	assert.NoError(t, err)
+	assert.Equal(t, Port(80), port)
+
+	i, err = Convert[int](nilPointer)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, i)
+	assert.Equal(t, 5, ConvertOr(nilPointer, 5))
+}
+
+func TestConvertBigNumbers(t *testing.T) {
+	var rangeErr *NumberRangeError
+
+	i64, err := ConvertToInt64(big.NewInt(-12), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-12), i64)
+
+	u64, err := ConvertToUint64(new(big.Int).SetUint64(math.MaxUint64), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), u64)
+
+	_, err = ConvertToInt64(new(big.Int).SetUint64(math.MaxUint64), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	huge, _ := new(big.Int).SetString("-100000000000000000000000", 10)
+	_, err = ConvertToInt64(huge, 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberUnderflow, rangeErr.Reason)
+
+	_, err = ConvertToUint8(*big.NewInt(256), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+
+	f64, err := ConvertToFloat64(huge, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, -1e23, f64)
+
+	i8, err := ConvertToInt8(big.NewFloat(-7), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-7), i8)
+
+	_, err = ConvertToInt8WithOptions(big.NewFloat(2.5), 0, nil)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	_, err = ConvertToInt64(new(big.Float).SetInf(false), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	var nilBig *big.Int
+	i64, err = ConvertToInt64(nilBig, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), i64)
+	assert.Equal(t, "12", ConvertToString(big.NewInt(12)))
+}
+
+func TestConvertWithNumberFormat(t *testing.T) {
+	lenient := &ConvertOptions{NumberFormat: LenientNumberFormat()}
+
+	i64, err := ConvertToInt64WithOptions(" 42", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), i64)
+
+	i, err := ConvertToIntWithOptions("0x1F", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, 31, i)
+
+	u32, err := ConvertToUint32WithOptions("1_000", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1000), u32)
+
+	i16, err := ConvertToInt16WithOptions("1e3", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, int16(1000), i16)
+
+	u64, err := ConvertToUint64WithOptions("12.0", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), u64)
+
+	f64, err := ConvertToFloat64WithOptions("1,250.5", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, 1250.5, f64)
+
+	f32, err := ConvertToFloat32WithOptions("12.5%", 0, lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.125), f32)
+
+	// strings must be exact when converted to integers
+	var rangeErr *NumberRangeError
+	_, err = ConvertToInt64WithOptions("32.2", 0, lenient)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	_, err = ConvertToIntWithOptions("50%", 0, lenient)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberFraction, rangeErr.Reason)
+
+	// range checks still apply
+	_, err = ConvertToInt8WithOptions("1e3", 0, lenient)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToUint8WithOptions("-0x01", 0, lenient)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNegative, rangeErr.Reason)
+
+	i8, err := ConvertToInt8WithOptions("1e3", 0, &ConvertOptions{Saturate: true, NumberFormat: LenientNumberFormat()})
+	assert.NoError(t, err)
+	assert.Equal(t, int8(127), i8)
+
+	// syntax errors return the default value
+	i64, err = ConvertToInt64WithOptions("abc", 9, lenient)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Equal(t, int64(9), i64)
+
+	// without a format strings are parsed as before
+	_, err = ConvertToInt64(" 42", 0)
+	assert.Error(t, err)
+	_, err = ConvertToInt64("12.0", 0)
+	assert.Error(t, err)
+
+	// through the generic conversion
+	port, err := ConvertWithOptions[uint16]("8,080", lenient)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(8080), port)
+}
+
+func TestConvertToBoolVocabulary(t *testing.T) {
+	for _, token := range []string{"1", "true", "T", "yes", "Y", " on ", "Enabled"} {
+		b, err := ConvertToBoolWithOptions(token, &ConvertOptions{StrictBool: true})
+		assert.NoError(t, err, token)
+		assert.True(t, b, token)
+	}
+
+	for _, token := range []string{"0", "false", "F", "no", "N", "OFF", "disabled", ""} {
+		b, err := ConvertToBoolWithOptions(token, &ConvertOptions{StrictBool: true})
+		assert.NoError(t, err, token)
+		assert.False(t, b, token)
+	}
+
+	// unrecognized strings
+	b, err := ConvertToBool("maybe")
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	_, err = ConvertToBoolWithOptions("maybe", &ConvertOptions{StrictBool: true})
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	_, err = ConvertWithOptions[bool]("2", &ConvertOptions{StrictBool: true})
+	var conversionErr *ConversionError
+	assert.ErrorAs(t, err, &conversionErr)
+}
+
+func TestRegisterBoolTokens(t *testing.T) {
+	strict := &ConvertOptions{StrictBool: true}
+
+	_, err := ConvertToBoolWithOptions("ja", strict)
+	assert.Error(t, err)
+
+	RegisterBoolTokens(true, "ja", "Oui", "sí")
+	RegisterBoolTokens(false, "nein", "non")
+	defer func() {
+		boolTokensMutex.Lock()
+		for _, token := range []string{"ja", "oui", "sí", "nein", "non"} {
+			delete(boolTokens, token)
+		}
+		boolTokensMutex.Unlock()
+	}()
+
+	b, err := ConvertToBoolWithOptions("JA", strict)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBoolWithOptions("oui", strict)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBoolWithOptions("Sí", strict)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBoolWithOptions("nein", strict)
+	assert.NoError(t, err)
+	assert.False(t, b)
+}
+
+func TestConvertToBoolNonPrimitive(t *testing.T) {
+	strict := &ConvertOptions{StrictBool: true, RejectUnsupported: true}
+
+	// non-nil structs, maps and slices are true, where earlier
+	// versions returned false
+	b, err := ConvertToBool(strings.Builder{})
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBool(&struct{ Name string }{})
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBool(map[string]int{})
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBool([]int{1})
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	_, err = ConvertToBoolWithOptions(strings.Builder{}, strict)
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	// the options are independent
+	b, err = ConvertToBoolWithOptions(strings.Builder{}, &ConvertOptions{StrictBool: true})
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = ConvertToBoolWithOptions("maybe", &ConvertOptions{RejectUnsupported: true})
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	// nil pointers are false
+	var builder *strings.Builder
+	b, err = ConvertToBoolWithOptions(builder, strict)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	// zero primitives stay false
+	b, err = ConvertToBoolWithOptions(uintptr(0), strict)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	// stringers are matched by their text
+	b, err = ConvertToBoolWithOptions(testStringer{"yes"}, strict)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	_, err = ConvertToBoolWithOptions(testStringer{"later"}, strict)
+	assert.Error(t, err)
+}
@@ -0,0 +1,379 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// Defines the unit of numeric timestamps counted from the Unix
+// epoch, as converted by `ConvertToTime`.
+//
+type EpochUnit int
+
+const (
+	// detect the unit from the magnitude of the value
+	EpochAuto EpochUnit = iota
+
+	// seconds since the epoch, as by `time.Unix`
+	EpochSeconds
+
+	// milliseconds since the epoch, as by `time.UnixMilli`
+	EpochMilliseconds
+
+	// microseconds since the epoch, as by `time.UnixMicro`
+	EpochMicroseconds
+
+	// nanoseconds since the epoch
+	EpochNanoseconds
+)
+
+// the number of nanoseconds in each unit
+var epochUnitNanos = map[EpochUnit]float64{
+	EpochSeconds:      1e9,
+	EpochMilliseconds: 1e6,
+	EpochMicroseconds: 1e3,
+	EpochNanoseconds:  1,
+}
+
+// detect the unit of the timestamp from its magnitude: values
+// below 1e11 are seconds, which covers the years up to 5138,
+// and each further factor of 1000 moves to the next unit
+func detectEpochUnit(magnitude float64) EpochUnit {
+	magnitude = math.Abs(magnitude)
+
+	switch {
+	case magnitude < 1e11:
+		return EpochSeconds
+
+	case magnitude < 1e14:
+		return EpochMilliseconds
+
+	case magnitude < 1e17:
+		return EpochMicroseconds
+	}
+
+	return EpochNanoseconds
+}
+
+var timeLayoutsMutex sync.RWMutex
+
+// the layouts tried in order when parsing times from strings
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.RubyDate,
+	time.UnixDate,
+	time.ANSIC,
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+//
+// Register additional layouts, in the format of `time.Parse`,
+// that `ConvertToTime` tries when parsing strings. Registered
+// layouts are tried after the built-in ones.
+//
+func RegisterTimeLayouts(layouts ...string) {
+	timeLayoutsMutex.Lock()
+	defer timeLayoutsMutex.Unlock()
+
+	timeLayouts = append(timeLayouts, layouts...)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// `time.Time`. Returns the `defaultValue` if the value is `nil`.
+// Accepts `time.Time` values, and numbers as timestamps since
+// the Unix epoch in the `EpochUnit` of the options, detecting
+// the unit from the magnitude by default. Strings are read as
+// timestamps if they are numbers, as `now` optionally followed
+// by an offset in the format of `ParseDuration` like `now-1h`,
+// or in one of the common layouts such as RFC 3339 and
+// `2006-01-02 15:04:05`, and those added with
+// `RegisterTimeLayouts`. Returns a `*strconv.NumError` for
+// strings that are not a time. Times written without a zone
+// and timestamps are in the `Location` of the options, or UTC.
+// For other non-primitive values the `defaultValue` is
+// returned, or an `ErrUnsupportedConversion` if the options
+// ask to `RejectUnsupported`.
+//
+func ConvertToTime(value interface{}, defaultValue time.Time) (time.Time, error) {
+	return ConvertToTimeWithOptions(value, defaultValue, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// `time.Time` as `ConvertToTime` does, using the given options.
+//
+func ConvertToTimeWithOptions(value interface{}, defaultValue time.Time, option *ConvertOptions) (time.Time, error) {
+	converted, err := convertToTime(value, defaultValue, option)
+	if err == ErrUnsupportedConversion && !option.rejectUnsupported() {
+		return defaultValue, nil
+	}
+
+	if err != nil {
+		return defaultValue, err
+	}
+
+	return converted, nil
+}
+
+// convert the value to a time, returning an
+// `ErrUnsupportedConversion` for non-primitive values
+func convertToTime(value interface{}, defaultValue time.Time, options *ConvertOptions) (time.Time, error) {
+	location := options.location()
+
+	switch v := value.(type) {
+	case time.Time:
+		return inLocation(v, location), nil
+
+	case *time.Time:
+		if v == nil {
+			return defaultValue, nil
+		}
+		return inLocation(*v, location), nil
+	}
+
+	primitive := primitiveOf(value)
+	if primitive == nil {
+		return defaultValue, nil
+	}
+
+	if text, ok := primitive.(string); ok {
+		return parseTime(text, options)
+	}
+
+	number, ok := numberOf(primitive)
+	if !ok {
+		return defaultValue, ErrUnsupportedConversion
+	}
+
+	return epochTime(number, primitive, options)
+}
+
+// convert the timestamp to a time in the unit of the options
+func epochTime(number numberValue, source interface{}, options *ConvertOptions) (time.Time, error) {
+	location := options.location()
+	unit := options.epochUnit()
+	if unit == EpochAuto {
+		unit = detectEpochUnit(number.float())
+	}
+
+	if number.kind == numberUnsigned {
+		if number.unsigned > math.MaxInt64 {
+			return time.Time{}, &NumberRangeError{Value: ConvertToString(source), Target: "time.Time", Reason: NumberOverflow}
+		}
+		number = numberValue{kind: numberSigned, signed: int64(number.unsigned)}
+	}
+
+	if number.kind == numberSigned {
+		var converted time.Time
+		switch unit {
+		case EpochSeconds:
+			converted = time.Unix(number.signed, 0)
+		case EpochMilliseconds:
+			converted = time.UnixMilli(number.signed)
+		case EpochMicroseconds:
+			converted = time.UnixMicro(number.signed)
+		default:
+			converted = time.Unix(0, number.signed)
+		}
+
+		return inLocation(converted.UTC(), location), nil
+	}
+
+	if math.IsNaN(number.floating) {
+		return time.Time{}, &NumberRangeError{Value: ConvertToString(source), Target: "time.Time", Reason: NumberNaN}
+	}
+
+	nanos := number.floating * epochUnitNanos[unit]
+	if math.Abs(nanos) < math.Ldexp(1, 63) {
+		return inLocation(time.Unix(0, int64(math.Round(nanos))).UTC(), location), nil
+	}
+
+	// too far out for nanoseconds, split off the whole seconds
+	seconds := nanos / 1e9
+	if math.Abs(seconds) >= math.Ldexp(1, 63) {
+		reason := NumberOverflow
+		if seconds < 0 {
+			reason = NumberUnderflow
+		}
+		return time.Time{}, &NumberRangeError{Value: ConvertToString(source), Target: "time.Time", Reason: reason}
+	}
+
+	whole := math.Floor(seconds)
+	converted := time.Unix(int64(whole), int64(math.Round((seconds-whole)*1e9)))
+	return inLocation(converted.UTC(), location), nil
+}
+
+// parse the time from a timestamp, a relative expression or
+// one of the known layouts
+func parseTime(text string, options *ConvertOptions) (time.Time, error) {
+	trimmed := strings.TrimSpace(text)
+
+	if number, err := ParseNumber(trimmed, options.numberFormat()); err == nil {
+		parsed, _ := numberOf(number)
+		return epochTime(parsed, trimmed, options)
+	}
+
+	if relative, ok, err := parseRelativeTime(text, trimmed, options); ok {
+		return relative, err
+	}
+
+	location := options.location()
+	parseLocation := location
+	if parseLocation == nil {
+		parseLocation = time.UTC
+	}
+
+	timeLayoutsMutex.RLock()
+	defer timeLayoutsMutex.RUnlock()
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, trimmed, parseLocation); err == nil {
+			return inLocation(parsed, location), nil
+		}
+	}
+
+	return time.Time{}, &strconv.NumError{Func: "ConvertToTime", Num: text, Err: strconv.ErrSyntax}
+}
+
+// parse expressions like `now`, `now-1h` and `now + 2d`,
+// returning `false` if the text is not relative to now
+func parseRelativeTime(text string, trimmed string, options *ConvertOptions) (time.Time, bool, error) {
+	if len(trimmed) < 3 || !strings.EqualFold(trimmed[:3], "now") {
+		return time.Time{}, false, nil
+	}
+
+	now := inLocation(options.now(), options.location())
+
+	offset := strings.TrimSpace(trimmed[3:])
+	if offset == "" {
+		return now, true, nil
+	}
+
+	syntaxError := &strconv.NumError{Func: "ConvertToTime", Num: text, Err: strconv.ErrSyntax}
+	if offset[0] != '-' && offset[0] != '+' {
+		return time.Time{}, true, syntaxError
+	}
+
+	amount := strings.TrimSpace(offset[1:])
+	if strings.HasPrefix(amount, "-") || strings.HasPrefix(amount, "+") {
+		return time.Time{}, true, syntaxError
+	}
+
+	duration, err := ParseDuration(amount)
+	if err != nil {
+		return time.Time{}, true, syntaxError
+	}
+
+	if offset[0] == '-' {
+		duration = -duration
+	}
+
+	return now.Add(duration), true, nil
+}
+
+// move the time to the location, if one is given
+func inLocation(value time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return value
+	}
+
+	return value.In(location)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// `time.Duration`. Returns the `defaultValue` if the value is
+// `nil`. Strings are parsed with `ParseDuration`, so that `90s`
+// and `3d4h` are accepted; numbers, including strings that are
+// numbers, are nanoseconds as for `time.Duration` itself and
+// are rounded to the nearest nanosecond. For other
+// non-primitive values the `defaultValue` is returned, or an
+// `ErrUnsupportedConversion` if the options ask to
+// `RejectUnsupported`.
+//
+func ConvertToDuration(value interface{}, defaultValue time.Duration) (time.Duration, error) {
+	return ConvertToDurationWithOptions(value, defaultValue, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// `time.Duration` as `ConvertToDuration` does, using the given
+// options.
+//
+func ConvertToDurationWithOptions(value interface{}, defaultValue time.Duration, option *ConvertOptions) (time.Duration, error) {
+	converted, err := convertToDuration(value, defaultValue, option)
+	if err == ErrUnsupportedConversion && !option.rejectUnsupported() {
+		return defaultValue, nil
+	}
+
+	if err != nil {
+		return defaultValue, err
+	}
+
+	return converted, nil
+}
+
+// convert the value to a duration, returning an
+// `ErrUnsupportedConversion` for non-primitive values
+func convertToDuration(value interface{}, defaultValue time.Duration, options *ConvertOptions) (time.Duration, error) {
+	if duration, ok := value.(time.Duration); ok {
+		return duration, nil
+	}
+
+	primitive := primitiveOf(value)
+	if primitive == nil {
+		return defaultValue, nil
+	}
+
+	if text, ok := primitive.(string); ok {
+		parsed, err := ParseNumber(text, options.numberFormat())
+		if err != nil {
+			return ParseDuration(text)
+		}
+		primitive = parsed
+	}
+
+	number, ok := numberOf(primitive)
+	if !ok {
+		return defaultValue, ErrUnsupportedConversion
+	}
+
+	// round fractions of a nanosecond
+	if number.kind == numberFloat {
+		primitive = math.Round(number.floating)
+	}
+
+	nanos, err := convertToSigned[int64](primitive, int64(defaultValue), 64, "time.Duration", options)
+	return time.Duration(nanos), err
+}
@@ -0,0 +1,298 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToTimeValues(t *testing.T) {
+	fallback := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	instant := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	converted, err := ConvertToTime(nil, fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, fallback, converted)
+
+	var missing *time.Time
+	converted, err = ConvertToTime(missing, fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, fallback, converted)
+
+	converted, err = ConvertToTime(instant, fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, instant, converted)
+
+	converted, err = ConvertToTime(&instant, fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, instant, converted)
+
+	// non-primitive values
+	converted, err = ConvertToTime(struct{}{}, fallback)
+	assert.NoError(t, err)
+	assert.Equal(t, fallback, converted)
+
+	_, err = ConvertToTimeWithOptions(struct{}{}, fallback, &ConvertOptions{RejectUnsupported: true})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+}
+
+func TestConvertToTimeEpoch(t *testing.T) {
+	instant := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	// auto-detected by magnitude
+	for _, value := range []interface{}{
+		instant.Unix(),
+		instant.UnixMilli(),
+		instant.UnixMicro(),
+		instant.UnixNano(),
+		uint32(instant.Unix()),
+		float64(instant.Unix()),
+		strconv.FormatInt(instant.UnixMilli(), 10),
+	} {
+		converted, err := ConvertToTime(value, time.Time{})
+		assert.NoError(t, err, value)
+		assert.True(t, instant.Equal(converted), value)
+		assert.Equal(t, time.UTC, converted.Location())
+	}
+
+	converted, err := ConvertToTime(1.5, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(1, 5e8).UTC(), converted)
+
+	converted, err = ConvertToTime(-1, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(-1, 0).UTC(), converted)
+
+	// explicit units
+	converted, err = ConvertToTimeWithOptions(1000, time.Time{}, &ConvertOptions{EpochUnit: EpochMilliseconds})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(1, 0).UTC(), converted)
+
+	converted, err = ConvertToTimeWithOptions(1000, time.Time{}, &ConvertOptions{EpochUnit: EpochNanoseconds})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(0, 1000).UTC(), converted)
+
+	converted, err = ConvertToTimeWithOptions(instant.UnixMilli(), time.Time{}, &ConvertOptions{EpochUnit: EpochSeconds})
+	assert.NoError(t, err)
+	assert.Equal(t, instant.UnixMilli(), converted.Unix())
+
+	converted, err = ConvertToTimeWithOptions(1e12, time.Time{}, &ConvertOptions{EpochUnit: EpochSeconds})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1e12), converted.Unix())
+
+	// out of range
+	var rangeErr *NumberRangeError
+	_, err = ConvertToTime(uint64(math.MaxUint64), time.Time{})
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToTime(math.NaN(), time.Time{})
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNaN, rangeErr.Reason)
+
+	_, err = ConvertToTimeWithOptions(-1e300, time.Time{}, &ConvertOptions{EpochUnit: EpochSeconds})
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberUnderflow, rangeErr.Reason)
+}
+
+func TestConvertToTimeLayouts(t *testing.T) {
+	instant := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	for _, text := range []string{
+		"2022-03-04T05:06:07Z",
+		"2022-03-04T05:06:07.000Z",
+		"2022-03-04T07:06:07+02:00",
+		"2022-03-04T05:06:07",
+		"2022-03-04 05:06:07",
+		" 2022-03-04 05:06:07Z ",
+		"2022/03/04 05:06:07",
+		"Fri, 04 Mar 2022 05:06:07 +0000",
+		"Fri, 04 Mar 2022 05:06:07 UTC",
+		"Fri Mar  4 05:06:07 2022",
+	} {
+		converted, err := ConvertToTime(text, time.Time{})
+		assert.NoError(t, err, text)
+		assert.True(t, instant.Equal(converted), text)
+	}
+
+	// the zone of the value is kept
+	converted, err := ConvertToTime("2022-03-04T07:06:07+02:00", time.Time{})
+	assert.NoError(t, err)
+	_, offset := converted.Zone()
+	assert.Equal(t, 7200, offset)
+
+	for _, text := range []string{"2022-03-04", "Mar 4, 2022", "March 4, 2022", "4 Mar 2022", "2022/03/04"} {
+		converted, err = ConvertToTime(text, time.Time{})
+		assert.NoError(t, err, text)
+		assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), converted, text)
+	}
+
+	fallback := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, text := range []string{"", "yesterday", "2022-13-01", "04.03.2022"} {
+		converted, err = ConvertToTime(text, fallback)
+		assert.ErrorIs(t, err, strconv.ErrSyntax, text)
+		assert.Equal(t, fallback, converted)
+	}
+
+	// registered layouts
+	layouts := timeLayouts
+	defer func() {
+		timeLayoutsMutex.Lock()
+		timeLayouts = layouts
+		timeLayoutsMutex.Unlock()
+	}()
+
+	RegisterTimeLayouts("02.01.2006")
+	converted, err = ConvertToTime("04.03.2022", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), converted)
+}
+
+func TestConvertToTimeLocation(t *testing.T) {
+	zone := time.FixedZone("IST", 5*3600+1800)
+	options := &ConvertOptions{Location: zone}
+
+	// times without a zone are in the location
+	converted, err := ConvertToTimeWithOptions("2022-03-04 10:36:07", time.Time{}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, zone, converted.Location())
+	assert.Equal(t, int64(1646370367), converted.Unix())
+
+	// other times are moved to it
+	converted, err = ConvertToTimeWithOptions("2022-03-04T05:06:07Z", time.Time{}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, zone, converted.Location())
+	assert.Equal(t, 10, converted.Hour())
+
+	converted, err = ConvertToTimeWithOptions(int64(1646370367), time.Time{}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, zone, converted.Location())
+	assert.Equal(t, 36, converted.Minute())
+}
+
+func TestConvertToTimeRelative(t *testing.T) {
+	now := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	options := &ConvertOptions{Now: now}
+
+	tests := map[string]time.Time{
+		"now":        now,
+		" NOW ":      now,
+		"now-1h":     now.Add(-time.Hour),
+		"now + 2d":   now.Add(48 * time.Hour),
+		"now+1h30m":  now.Add(90 * time.Minute),
+		"now - 1.5w": now.Add(-252 * time.Hour),
+		"now-0":      now,
+	}
+
+	for text, expected := range tests {
+		converted, err := ConvertToTimeWithOptions(text, time.Time{}, options)
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, converted, text)
+	}
+
+	for _, text := range []string{"now1h", "now--1h", "now-", "now-1x", "nowhere"} {
+		_, err := ConvertToTimeWithOptions(text, time.Time{}, options)
+		assert.ErrorIs(t, err, strconv.ErrSyntax, text)
+	}
+
+	// the current time without options
+	before := time.Now()
+	converted, err := ConvertToTime("now", time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, converted.Before(before))
+	assert.False(t, converted.After(time.Now()))
+}
+
+func TestConvertToDuration(t *testing.T) {
+	duration, err := ConvertToDuration(nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, duration)
+
+	duration, err = ConvertToDuration(time.Minute, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, duration)
+
+	duration, err = ConvertToDuration("3d4h", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 76*time.Hour, duration)
+
+	duration, err = ConvertToDuration([]byte("90s"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, duration)
+
+	// numbers are nanoseconds
+	duration, err = ConvertToDuration(1500, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Nanosecond, duration)
+
+	duration, err = ConvertToDuration("1500", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Nanosecond, duration)
+
+	duration, err = ConvertToDuration("soon", time.Second)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Equal(t, time.Second, duration)
+
+	var rangeErr *NumberRangeError
+	_, err = ConvertToDuration(uint64(math.MaxUint64), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, "time.Duration", rangeErr.Target)
+
+	// fractions of a nanosecond are rounded
+	duration, err = ConvertToDuration("1.5", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Nanosecond, duration)
+
+	duration, err = ConvertToDuration(-2.4, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, -2*time.Nanosecond, duration)
+
+	_, err = ConvertToDuration("99999999999999999999.5", 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ConvertToDuration(math.NaN(), 0)
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNaN, rangeErr.Reason)
+
+	duration, err = ConvertToDuration(struct{}{}, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, duration)
+
+	_, err = ConvertToDurationWithOptions(struct{}{}, time.Second, &ConvertOptions{RejectUnsupported: true})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+}
+
+func TestConvertTimeGeneric(t *testing.T) {
+	converted, err := Convert[time.Time]("2022-03-04")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), converted)
+
+	duration, err := Convert[time.Duration]("1m30s")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, duration)
+
+	_, err = Convert[time.Time]("someday")
+	var conversionErr *ConversionError
+	assert.ErrorAs(t, err, &conversionErr)
+	assert.Equal(t, timeType, conversionErr.TargetType)
+
+	_, err = Convert[time.Time]([]int{1})
+	assert.ErrorIs(t, err, ErrUnsupportedConversion)
+
+	assert.Equal(t, time.Second, ConvertOr[time.Duration]("later", time.Second))
+}
@@ -0,0 +1,625 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// Returned by `DecodeMap` when the target is not a non-nil
+// pointer to a struct.
+//
+var ErrInvalidDecodeTarget = errors.New("Target must be a non-nil pointer to a struct")
+
+//
+// Reported by `DecodeMap` for fields tagged as `required` that
+// have no value and no default.
+//
+var ErrRequiredField = errors.New("Required field is missing")
+
+//
+// Options that control how `DecodeMap` reads struct fields and
+// converts values. A `nil` options value uses the defaults.
+//
+type DecodeOptions struct {
+	TagName        string          // struct tag to read field names and options from, defaults to `berry`
+	ConvertOptions *ConvertOptions // options used to convert values, see `ConvertOptions`
+	SplitStrings   bool            // split strings decoded into slices at the convert `Separator`
+}
+
+// return the struct tag to read
+func (options *DecodeOptions) tagName() string {
+	if options == nil || options.TagName == "" {
+		return "berry"
+	}
+
+	return options.TagName
+}
+
+// return the separator to split strings decoded into slices
+// at, or an empty string to keep them whole
+func (options *DecodeOptions) splitSeparator() string {
+	if options == nil || !options.SplitStrings {
+		return ""
+	}
+
+	return options.ConvertOptions.separator()
+}
+
+// return the options used to convert values
+func (options *DecodeOptions) convertOptions() *ConvertOptions {
+	if options == nil {
+		return nil
+	}
+
+	return options.ConvertOptions
+}
+
+//
+// Error reported for a single field. `Path` locates the value
+// in the decoded data, like `servers[0].port`, in the syntax
+// accepted by `GetPath`.
+//
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+//
+// Return the error message.
+//
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("Field %s: %s", e.Path, e.Err.Error())
+}
+
+//
+// Return the wrapped error.
+//
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+//
+// All errors reported for the fields of a struct, in the order
+// of the fields.
+//
+type FieldErrors []*FieldError
+
+//
+// Return the error message combining all errors.
+//
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for index, err := range e {
+		messages[index] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// the options read from a struct tag
+type fieldTag struct {
+	name         string
+	skip         bool
+	required     bool
+	omitEmpty    bool
+	hasDefault   bool
+	defaultValue string
+}
+
+// parse a tag like `port,required,default=8080` or
+// `port,omitempty`. The default takes the rest of the tag, so
+// it comes last and may contain commas.
+func parseFieldTag(tag string) fieldTag {
+	if tag == "-" {
+		return fieldTag{skip: true}
+	}
+
+	name, rest := tag, ""
+	if index := strings.IndexByte(tag, ','); index >= 0 {
+		name, rest = tag[:index], tag[index+1:]
+	}
+
+	parsed := fieldTag{name: name}
+	for rest != "" {
+		if strings.HasPrefix(rest, "default=") {
+			parsed.hasDefault = true
+			parsed.defaultValue = strings.TrimPrefix(rest, "default=")
+			break
+		}
+
+		option := rest
+		if index := strings.IndexByte(rest, ','); index >= 0 {
+			option, rest = rest[:index], rest[index+1:]
+		} else {
+			rest = ""
+		}
+
+		switch option {
+		case "required":
+			parsed.required = true
+
+		case "omitempty":
+			parsed.omitEmpty = true
+		}
+	}
+
+	return parsed
+}
+
+//
+// Decode the loosely typed data, such as that read from YAML,
+// query parameters or the environment, into the struct that
+// `target` points to. Each exported field is read from the key
+// named in its `berry` tag, like `berry:"port,default=8080"`,
+// or from the key matching its name ignoring case. A tag of
+// `-` skips the field, `required` reports the field if the key
+// is missing, and `default=` gives the value to decode when it
+// is missing. Values are converted weakly with the `ConvertTo*`
+// functions, so that `"8080"` is read into an `int` and `"true"`
+// into a `bool`. Nested structs are decoded from nested maps,
+// slices from slices, arrays or a single value, and maps from
+// maps. Strings are split into slices as by `ConvertToSlice`
+// only if `SplitStrings` is set. Pointers are allocated as
+// needed, and fields of embedded structs without a tag name
+// are read from the same map unless the outer struct has a
+// field with the same name.
+// Types implementing `MapValueUnmarshaler` decode themselves,
+// as do types implementing `encoding.TextUnmarshaler` for
+// strings and `json.Unmarshaler` for the value written as JSON.
+// Returns `ErrInvalidDecodeTarget` if target is not a pointer
+// to a struct, or `FieldErrors` listing every field that could
+// not be decoded.
+//
+func DecodeMap(data map[string]interface{}, target interface{}, options *DecodeOptions) error {
+	reflected := reflect.ValueOf(target)
+	if reflected.Kind() != reflect.Ptr || reflected.IsNil() || reflected.Elem().Kind() != reflect.Struct {
+		return ErrInvalidDecodeTarget
+	}
+
+	decoder := &mapDecoder{
+		tagName:   options.tagName(),
+		convert:   options.convertOptions(),
+		separator: options.splitSeparator(),
+	}
+
+	decoder.decodeFields(newDecodeEntries(data), reflected.Elem(), "", nil)
+	if len(decoder.errs) > 0 {
+		return decoder.errs
+	}
+
+	return nil
+}
+
+//
+// Implemented by types that decode themselves from the loosely
+// typed value given to `DecodeMap`, the counterpart of
+// `MapValueMarshaler`.
+//
+type MapValueUnmarshaler interface {
+	UnmarshalMapValue(value interface{}) error
+}
+
+var mapValueUnmarshalerType = reflect.TypeOf((*MapValueUnmarshaler)(nil)).Elem()
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// the interfaces of types that marshal or unmarshal themselves
+var valueHookTypes = []reflect.Type{
+	mapValueMarshalerType,
+	jsonMarshalerType,
+	textMarshalerType,
+	mapValueUnmarshalerType,
+	jsonUnmarshalerType,
+	textUnmarshalerType,
+}
+
+// holds the state of a single `DecodeMap` call
+type mapDecoder struct {
+	tagName   string
+	convert   *ConvertOptions
+	separator string
+	errs      FieldErrors
+}
+
+// record the error for the field at the path
+func (decoder *mapDecoder) fail(path string, err error) {
+	decoder.errs = append(decoder.errs, &FieldError{Path: path, Err: err})
+}
+
+// record that the value cannot be converted to the type
+func (decoder *mapDecoder) failConversion(path string, value interface{}, target reflect.Type, err error) {
+	decoder.fail(path, &ConversionError{
+		Value:      value,
+		SourceType: reflect.TypeOf(value),
+		TargetType: target,
+		Cause:      err,
+	})
+}
+
+// decode the entries into the fields of the struct, returning
+// `true` if any field was assigned a value or default. Fields
+// named in `hidden` are shadowed by an outer struct.
+func (decoder *mapDecoder) decodeFields(entries *decodeEntries, target reflect.Value, path string, hidden map[string]bool) bool {
+	assigned := false
+	structType := target.Type()
+
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag := parseFieldTag(field.Tag.Get(decoder.tagName))
+		if tag.skip {
+			continue
+		}
+
+		if isInlinedField(field, tag) {
+			embeddedHidden := mergeFieldNames(hidden, directFieldNames(structType, decoder.tagName))
+			if decoder.decodeEmbedded(entries, field, target.Field(index), path, embeddedHidden) {
+				assigned = true
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := tag.name
+		if name == "" {
+			name = field.Name
+		}
+
+		if hidden[name] {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, name)
+		value, found := entries.lookup(name)
+		switch {
+		case found && !isNilValue(value):
+			decoder.decodeValue(value, target.Field(index), fieldPath)
+			assigned = true
+
+		case tag.hasDefault:
+			decoder.decodeValue(tag.defaultValue, target.Field(index), fieldPath)
+			assigned = true
+
+		case tag.required:
+			decoder.fail(fieldPath, ErrRequiredField)
+		}
+	}
+
+	return assigned
+}
+
+// decode the fields of an embedded struct from the same entries,
+// returning `true` if any field was assigned
+func (decoder *mapDecoder) decodeEmbedded(entries *decodeEntries, field reflect.StructField, target reflect.Value, path string, hidden map[string]bool) bool {
+	if target.Kind() == reflect.Struct {
+		return decoder.decodeFields(entries, target, path, hidden)
+	}
+
+	// only allocate the pointer if one of its fields is set,
+	// pointers to unexported structs cannot be allocated
+	embedded := target
+	if target.IsNil() {
+		if field.PkgPath != "" {
+			return false
+		}
+		embedded = reflect.New(field.Type.Elem())
+	}
+
+	if !decoder.decodeFields(entries, embedded.Elem(), path, hidden) {
+		return false
+	}
+
+	if target.IsNil() {
+		target.Set(embedded)
+	}
+
+	return true
+}
+
+// check if the field is an embedded struct, or pointer to one,
+// whose fields are read from and written to the outer map
+func isInlinedField(field reflect.StructField, tag fieldTag) bool {
+	if !field.Anonymous || tag.name != "" {
+		return false
+	}
+
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Struct && !hasValueHooks(fieldType)
+}
+
+// check if the type has its own marshaler or unmarshaler
+func hasValueHooks(valueType reflect.Type) bool {
+	pointerType := reflect.PtrTo(valueType)
+	for _, hookType := range valueHookTypes {
+		if pointerType.Implements(hookType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// return the names of the exported fields of the struct that
+// are not inlined, which hide fields of the same name in its
+// embedded structs
+func directFieldNames(structType reflect.Type, tagName string) map[string]bool {
+	names := make(map[string]bool)
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag := parseFieldTag(field.Tag.Get(tagName))
+		if tag.skip || field.PkgPath != "" || isInlinedField(field, tag) {
+			continue
+		}
+
+		if tag.name != "" {
+			names[tag.name] = true
+		} else {
+			names[field.Name] = true
+		}
+	}
+
+	return names
+}
+
+// return the union of the two sets of names
+func mergeFieldNames(first map[string]bool, second map[string]bool) map[string]bool {
+	merged := make(map[string]bool, len(first)+len(second))
+	for name := range first {
+		merged[name] = true
+	}
+	for name := range second {
+		merged[name] = true
+	}
+
+	return merged
+}
+
+// decode the value into the target, recording any errors
+func (decoder *mapDecoder) decodeValue(value interface{}, target reflect.Value, path string) {
+	if isNilValue(value) {
+		return
+	}
+
+	targetType := target.Type()
+	if reflect.TypeOf(value).AssignableTo(targetType) {
+		target.Set(reflect.ValueOf(value))
+		return
+	}
+
+	if decoder.decodeUnmarshaler(value, target, path) {
+		return
+	}
+
+	switch targetType.Kind() {
+	case reflect.Ptr:
+		pointer := reflect.New(targetType.Elem())
+		decoder.decodeValue(value, pointer.Elem(), path)
+		target.Set(pointer)
+
+	case reflect.Struct:
+		if targetType == timeType {
+			decoder.decodeConverted(value, target, path)
+			return
+		}
+
+		entries, ok := mapEntries(value)
+		if !ok {
+			decoder.failConversion(path, value, targetType, ErrUnsupportedConversion)
+			return
+		}
+		decoder.decodeFields(newDecodeEntries(entries), target, path, nil)
+
+	case reflect.Slice:
+		if targetType.Elem().Kind() == reflect.Uint8 {
+			if text, ok := primitiveOf(value).(string); ok {
+				target.SetBytes([]byte(text))
+				return
+			}
+		}
+
+		items, ok := sliceItems(value, decoder.separator)
+		if !ok {
+			decoder.failConversion(path, value, targetType, ErrUnsupportedConversion)
+			return
+		}
+
+		result := reflect.MakeSlice(targetType, len(items), len(items))
+		for index, item := range items {
+			decoder.decodeValue(item, result.Index(index), path+"["+strconv.Itoa(index)+"]")
+		}
+		target.Set(result)
+
+	case reflect.Array:
+		items, ok := sliceItems(value, decoder.separator)
+		if !ok {
+			decoder.failConversion(path, value, targetType, ErrUnsupportedConversion)
+			return
+		}
+
+		if len(items) > target.Len() {
+			decoder.fail(path, fmt.Errorf("Expected at most %d elements but got %d", target.Len(), len(items)))
+			return
+		}
+
+		for index, item := range items {
+			decoder.decodeValue(item, target.Index(index), path+"["+strconv.Itoa(index)+"]")
+		}
+
+	case reflect.Map:
+		if !IsMap(value) {
+			decoder.failConversion(path, value, targetType, ErrUnsupportedConversion)
+			return
+		}
+
+		source := reflect.ValueOf(value)
+		result := reflect.MakeMapWithSize(targetType, source.Len())
+		for _, key := range sortedMapKeys(source) {
+			keyPath := joinFieldPath(path, ConvertToString(key.Interface()))
+
+			mapKey := reflect.New(targetType.Key()).Elem()
+			errorCount := len(decoder.errs)
+			decoder.decodeValue(key.Interface(), mapKey, keyPath)
+			if len(decoder.errs) > errorCount {
+				continue
+			}
+
+			mapValue := reflect.New(targetType.Elem()).Elem()
+			decoder.decodeValue(source.MapIndex(key).Interface(), mapValue, keyPath)
+			result.SetMapIndex(mapKey, mapValue)
+		}
+		target.Set(result)
+
+	default:
+		decoder.decodeConverted(value, target, path)
+	}
+}
+
+// decode the value with the unmarshaler of the target, returning
+// `false` if it has none for the value
+func (decoder *mapDecoder) decodeUnmarshaler(value interface{}, target reflect.Value, path string) bool {
+	if target.Type() == timeType || target.Kind() == reflect.Ptr || !target.CanAddr() {
+		return false
+	}
+
+	var err error
+	pointer := target.Addr().Interface()
+	text, isText := primitiveOf(value).(string)
+
+	if unmarshaler, ok := pointer.(MapValueUnmarshaler); ok {
+		err = unmarshaler.UnmarshalMapValue(value)
+	} else if unmarshaler, ok := pointer.(encoding.TextUnmarshaler); ok && isText {
+		err = unmarshaler.UnmarshalText([]byte(text))
+	} else if unmarshaler, ok := pointer.(json.Unmarshaler); ok {
+		var data []byte
+		if data, err = json.Marshal(value); err == nil {
+			err = unmarshaler.UnmarshalJSON(data)
+		}
+	} else {
+		return false
+	}
+
+	if err != nil {
+		decoder.failConversion(path, value, target.Type(), err)
+	}
+
+	return true
+}
+
+// decode the value into the target using the `ConvertTo*`
+// function for its type
+func (decoder *mapDecoder) decodeConverted(value interface{}, target reflect.Value, path string) {
+	converted, err := convertToType(value, target.Type(), decoder.convert)
+	if err != nil {
+		decoder.failConversion(path, value, target.Type(), err)
+		return
+	}
+
+	target.Set(converted)
+}
+
+// return the entries of the map with keys converted to strings,
+// or `false` if the value is not a map
+func mapEntries(value interface{}) (map[string]interface{}, bool) {
+	if entries, ok := value.(map[string]interface{}); ok {
+		return entries, true
+	}
+
+	if !IsMap(value) {
+		return nil, false
+	}
+
+	reflected := reflect.ValueOf(value)
+	entries := make(map[string]interface{}, reflected.Len())
+	iterator := reflected.MapRange()
+	for iterator.Next() {
+		entries[ConvertToString(iterator.Key().Interface())] = iterator.Value().Interface()
+	}
+
+	return entries, true
+}
+
+// the entries of a map decoded into a struct, with an index
+// of the keys by their lower case, built on the first lookup
+// that needs it
+type decodeEntries struct {
+	values map[string]interface{}
+	folded map[string]string
+}
+
+// wrap the entries of a map for lookups
+func newDecodeEntries(values map[string]interface{}) *decodeEntries {
+	return &decodeEntries{values: values}
+}
+
+// find the entry for the name, preferring an exact match over
+// one that differs in case. Of the keys that differ only in
+// case, the first in sorted order is used.
+func (entries *decodeEntries) lookup(name string) (interface{}, bool) {
+	if value, found := entries.values[name]; found {
+		return value, true
+	}
+
+	if entries.folded == nil {
+		entries.folded = make(map[string]string, len(entries.values))
+		for key := range entries.values {
+			lower := strings.ToLower(key)
+			if existing, found := entries.folded[lower]; !found || key < existing {
+				entries.folded[lower] = key
+			}
+		}
+	}
+
+	if key, found := entries.folded[strings.ToLower(name)]; found {
+		return entries.values[key], true
+	}
+
+	return nil, false
+}
+
+// return the keys of the map sorted by their text
+func sortedMapKeys(reflected reflect.Value) []reflect.Value {
+	keys := reflected.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	return keys
+}
+
+// append the key to the path, quoting keys that the path syntax
+// would otherwise split
+func joinFieldPath(path string, key string) string {
+	if key == "" || key == "*" || strings.ContainsAny(key, ".[]\"") {
+		return path + "[\"" + key + "\"]"
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,389 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDatabase struct {
+	Host     string `berry:"host,required"`
+	Port     int    `berry:"port,default=5432"`
+	Replicas []string
+}
+
+type testAuditInfo struct {
+	Owner   string
+	Created time.Time `berry:"created"`
+}
+
+type testServerConfig struct {
+	testAuditInfo
+	*testLimits
+
+	Name     string            `berry:"name,required"`
+	Port     uint16            `berry:"port,default=8080"`
+	Debug    bool              `berry:"debug"`
+	Ratio    float32           `berry:"ratio"`
+	Timeout  time.Duration     `berry:"timeout,default=30s"`
+	Tags     []string          `berry:"tags,default=a,b"`
+	Database testDatabase      `berry:"db"`
+	Backup   *testDatabase     `berry:"backup"`
+	Weights  map[string]int    `berry:"weights"`
+	Servers  []testDatabase    `berry:"servers"`
+	Address  net.IP            `berry:"address"`
+	Extra    interface{}       `berry:"extra"`
+	Labels   map[int]bool      `berry:"labels"`
+	Ignored  string            `berry:"-"`
+	Pair     [2]int            `berry:"pair"`
+	Payload  []byte            `berry:"payload"`
+	Meta     map[string]string `berry:"meta"`
+
+	secret string
+}
+
+type testLimits struct {
+	MaxConnections int `berry:"maxConnections"`
+}
+
+func TestDecodeMap(t *testing.T) {
+	data := map[string]interface{}{
+		"name":    "api",
+		"port":    "9090",
+		"debug":   "yes",
+		"ratio":   0.5,
+		"timeout": "1m30s",
+		"db": map[string]interface{}{
+			"host":     "localhost",
+			"replicas": "replica-1",
+		},
+		"backup": map[interface{}]interface{}{
+			"host": "backup.local",
+			"port": 6543,
+		},
+		"weights": map[string]interface{}{"a": "1", "b": 2.0},
+		"servers": []interface{}{
+			map[string]interface{}{"host": "one", "port": "1"},
+			map[string]interface{}{"host": "two"},
+		},
+		"address":        "10.0.0.1",
+		"extra":          []int{1, 2},
+		"labels":         map[string]string{"1": "true", "2": "off"},
+		"Ignored":        "value",
+		"pair":           []string{"3", "4"},
+		"payload":        "bytes",
+		"meta":           map[string]interface{}{"a.b": 1},
+		"owner":          "ops",
+		"created":        "2022-03-04",
+		"maxConnections": "100",
+		"secret":         "hidden",
+	}
+
+	var config testServerConfig
+	err := DecodeMap(data, &config, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "api", config.Name)
+	assert.Equal(t, uint16(9090), config.Port)
+	assert.True(t, config.Debug)
+	assert.Equal(t, float32(0.5), config.Ratio)
+	assert.Equal(t, 90*time.Second, config.Timeout)
+	assert.Equal(t, []string{"a,b"}, config.Tags)
+	assert.Equal(t, testDatabase{Host: "localhost", Port: 5432, Replicas: []string{"replica-1"}}, config.Database)
+	assert.Equal(t, &testDatabase{Host: "backup.local", Port: 6543}, config.Backup)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, config.Weights)
+	assert.Equal(t, []testDatabase{{Host: "one", Port: 1}, {Host: "two", Port: 5432}}, config.Servers)
+	assert.Equal(t, "10.0.0.1", config.Address.String())
+	assert.Equal(t, []int{1, 2}, config.Extra)
+	assert.Equal(t, map[int]bool{1: true, 2: false}, config.Labels)
+	assert.Equal(t, "", config.Ignored)
+	assert.Equal(t, [2]int{3, 4}, config.Pair)
+	assert.Equal(t, []byte("bytes"), config.Payload)
+	assert.Equal(t, map[string]string{"a.b": "1"}, config.Meta)
+	assert.Equal(t, "ops", config.Owner)
+	assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), config.Created)
+	assert.Nil(t, config.testLimits)
+	assert.Equal(t, "", config.secret)
+}
+
+func TestDecodeMapDefaults(t *testing.T) {
+	var config testServerConfig
+	err := DecodeMap(map[string]interface{}{"name": "api", "db": map[string]interface{}{"host": "db"}, "backup": nil}, &config, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint16(8080), config.Port)
+	assert.Equal(t, 30*time.Second, config.Timeout)
+	assert.Equal(t, 5432, config.Database.Port)
+	assert.Nil(t, config.Backup)
+	assert.Nil(t, config.testLimits)
+	assert.Nil(t, config.Servers)
+
+	// existing values are kept
+	config = testServerConfig{Debug: true, testLimits: &testLimits{MaxConnections: 5}}
+	err = DecodeMap(map[string]interface{}{"NAME": "api", "DB": map[string]interface{}{"HOST": "db"}, "maxconnections": 7}, &config, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "api", config.Name)
+	assert.Equal(t, "db", config.Database.Host)
+	assert.True(t, config.Debug)
+	assert.Equal(t, 7, config.MaxConnections)
+}
+
+func TestDecodeEntriesLookup(t *testing.T) {
+	entries := newDecodeEntries(map[string]interface{}{"NAME": 1, "Name": 2, "nAME": 3, "port": 4})
+
+	value, found := entries.lookup("nAME")
+	assert.True(t, found)
+	assert.Equal(t, 3, value)
+
+	// of the keys that differ in case the first sorted one wins
+	for round := 0; round < 10; round++ {
+		value, found = entries.lookup("name")
+		assert.True(t, found)
+		assert.Equal(t, 1, value)
+	}
+
+	value, found = entries.lookup("PORT")
+	assert.True(t, found)
+	assert.Equal(t, 4, value)
+
+	_, found = entries.lookup("host")
+	assert.False(t, found)
+}
+
+func TestDecodeMapErrors(t *testing.T) {
+	data := map[string]interface{}{
+		"port":    "http",
+		"ratio":   []int{1},
+		"timeout": "soon",
+		"db":      "localhost",
+		"servers": []interface{}{
+			map[string]interface{}{"host": "one"},
+			map[string]interface{}{"port": "99999999999999999999"},
+		},
+		"weights": map[string]interface{}{"a": "x"},
+		"labels":  map[string]interface{}{"one": true},
+		"pair":    []int{1, 2, 3},
+		"address": "not-an-ip",
+	}
+
+	var config testServerConfig
+	err := DecodeMap(data, &config, nil)
+
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+
+	paths := make([]string, len(fieldErrors))
+	for index, fieldErr := range fieldErrors {
+		paths[index] = fieldErr.Path
+	}
+	assert.Equal(t, []string{"name", "port", "ratio", "timeout", "db", "weights.a", "servers[1].host", "servers[1].port", "address", "labels.one", "pair"}, paths)
+
+	assert.ErrorIs(t, fieldErrors[0], ErrRequiredField)
+	assert.Equal(t, "Field name: Required field is missing", fieldErrors[0].Error())
+	assert.ErrorIs(t, fieldErrors[1], strconv.ErrSyntax)
+	assert.Equal(t, `Field port: Cannot convert string "http" to uint16: invalid syntax`, fieldErrors[1].Error())
+	assert.ErrorIs(t, fieldErrors[2], ErrUnsupportedConversion)
+	assert.ErrorIs(t, fieldErrors[4], ErrUnsupportedConversion)
+
+	var rangeErr *NumberRangeError
+	assert.ErrorAs(t, fieldErrors[7], &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	// the paths locate the values in the data
+	value, pathErr := GetPath(data, fieldErrors[7].Path)
+	assert.NoError(t, pathErr)
+	assert.Equal(t, "99999999999999999999", value)
+
+	assert.Contains(t, err.Error(), "Field name: Required field is missing; Field port: ")
+}
+
+func TestDecodeMapOptions(t *testing.T) {
+	type settings struct {
+		Level   int    `env:"LOG_LEVEL"`
+		Verbose bool   `env:"VERBOSE"`
+		Path    string `berry:"ignored"`
+	}
+
+	var result settings
+	err := DecodeMap(map[string]interface{}{"LOG_LEVEL": "0x10", "VERBOSE": "1", "path": "/tmp"}, &result, &DecodeOptions{
+		TagName:        "env",
+		ConvertOptions: &ConvertOptions{NumberFormat: LenientNumberFormat()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, settings{Level: 16, Verbose: true, Path: "/tmp"}, result)
+
+	// strict conversions
+	err = DecodeMap(map[string]interface{}{"VERBOSE": "maybe"}, &result, &DecodeOptions{
+		TagName:        "env",
+		ConvertOptions: &ConvertOptions{StrictBool: true},
+	})
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, 1, len(fieldErrors))
+	assert.ErrorIs(t, fieldErrors[0], strconv.ErrSyntax)
+
+	// strings are split into slices only when asked
+	type list struct {
+		Tags  []string `berry:"tags"`
+		Ports [3]int   `berry:"ports"`
+	}
+
+	var kept list
+	assert.NoError(t, DecodeMap(map[string]interface{}{"tags": "a, b"}, &kept, nil))
+	assert.Equal(t, list{Tags: []string{"a, b"}}, kept)
+
+	var split list
+	err = DecodeMap(map[string]interface{}{"tags": "a, b", "ports": "80;443"}, &split, &DecodeOptions{
+		SplitStrings:   true,
+		ConvertOptions: &ConvertOptions{Separator: ";"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, list{Tags: []string{"a, b"}, Ports: [3]int{80, 443}}, split)
+
+	split = list{}
+	assert.NoError(t, DecodeMap(map[string]interface{}{"tags": "a, b"}, &split, &DecodeOptions{SplitStrings: true}))
+	assert.Equal(t, []string{"a", "b"}, split.Tags)
+}
+
+type testVersion struct {
+	Major int
+	Minor int
+}
+
+func (v *testVersion) UnmarshalMapValue(value interface{}) error {
+	parts := strings.SplitN(ConvertToString(value), ".", 2)
+	if len(parts) != 2 {
+		return errors.New("Invalid version")
+	}
+
+	var err error
+	if v.Major, err = strconv.Atoi(parts[0]); err != nil {
+		return err
+	}
+	v.Minor, err = strconv.Atoi(parts[1])
+	return err
+}
+
+type testRange struct {
+	Low  int
+	High int
+}
+
+func (r *testRange) UnmarshalJSON(data []byte) error {
+	var bounds [2]int
+	if err := json.Unmarshal(data, &bounds); err != nil {
+		return err
+	}
+
+	r.Low, r.High = bounds[0], bounds[1]
+	return nil
+}
+
+type testShadowBase struct {
+	Name  string `berry:"name"`
+	Level int    `berry:"level"`
+}
+
+type testShadowed struct {
+	testShadowBase
+	testVersion
+
+	Name    string      `berry:"name"`
+	Version testVersion `berry:"version"`
+	Range   testRange   `berry:"range"`
+	Since   time.Time   `berry:"since"`
+}
+
+func TestDecodeMapUnmarshalers(t *testing.T) {
+	var result testShadowed
+	err := DecodeMap(map[string]interface{}{
+		"name":        "outer",
+		"level":       3,
+		"version":     "2.5",
+		"testVersion": "3.1",
+		"range":       []interface{}{1, 10},
+		"since":       "2022-01-02",
+	}, &result, nil)
+	assert.NoError(t, err)
+
+	// the outer field hides the embedded one
+	assert.Equal(t, "outer", result.Name)
+	assert.Equal(t, "", result.testShadowBase.Name)
+	assert.Equal(t, 3, result.Level)
+
+	// unexported embedded types with unmarshalers are skipped
+	assert.Equal(t, testVersion{Major: 2, Minor: 5}, result.Version)
+	assert.Equal(t, testVersion{}, result.testVersion)
+	assert.Equal(t, testRange{Low: 1, High: 10}, result.Range)
+	assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), result.Since)
+
+	// unmarshaler errors are reported for the field
+	err = DecodeMap(map[string]interface{}{"version": "two", "range": "wide"}, &result, nil)
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, 2, len(fieldErrors))
+	assert.Equal(t, "version", fieldErrors[0].Path)
+	assert.Equal(t, "range", fieldErrors[1].Path)
+}
+
+func TestIsInlinedField(t *testing.T) {
+	shadowedType := reflect.TypeOf(testShadowed{})
+	configType := reflect.TypeOf(testServerConfig{})
+
+	assert.True(t, isInlinedField(shadowedType.Field(0), fieldTag{}))
+	assert.False(t, isInlinedField(shadowedType.Field(0), fieldTag{name: "base"}))
+	assert.False(t, isInlinedField(shadowedType.Field(1), fieldTag{}))
+	assert.False(t, isInlinedField(shadowedType.Field(2), fieldTag{}))
+	assert.True(t, isInlinedField(configType.Field(1), fieldTag{}))
+
+	assert.Equal(t, map[string]bool{"name": true, "version": true, "range": true, "since": true}, directFieldNames(shadowedType, "berry"))
+}
+
+func TestDecodeMapInvalidTarget(t *testing.T) {
+	var config testServerConfig
+	var missing *testServerConfig
+	number := 5
+
+	assert.ErrorIs(t, DecodeMap(nil, config, nil), ErrInvalidDecodeTarget)
+	assert.ErrorIs(t, DecodeMap(nil, missing, nil), ErrInvalidDecodeTarget)
+	assert.ErrorIs(t, DecodeMap(nil, &number, nil), ErrInvalidDecodeTarget)
+	assert.ErrorIs(t, DecodeMap(nil, nil, nil), ErrInvalidDecodeTarget)
+
+	// nil data only applies the defaults
+	assert.Error(t, DecodeMap(nil, &config, nil))
+	assert.Equal(t, uint16(8080), config.Port)
+}
+
+func TestParseFieldTag(t *testing.T) {
+	assert.Equal(t, fieldTag{name: "port"}, parseFieldTag("port"))
+	assert.Equal(t, fieldTag{skip: true}, parseFieldTag("-"))
+	assert.Equal(t, fieldTag{name: "port", required: true}, parseFieldTag("port,required"))
+	assert.Equal(t, fieldTag{required: true, hasDefault: true, defaultValue: "a,b=c"}, parseFieldTag(",required,default=a,b=c"))
+	assert.Equal(t, fieldTag{name: "x", hasDefault: true}, parseFieldTag("x,default="))
+	assert.Equal(t, fieldTag{name: "x", omitEmpty: true}, parseFieldTag("x,omitempty"))
+}
+
+func TestJoinFieldPath(t *testing.T) {
+	assert.Equal(t, "a", joinFieldPath("", "a"))
+	assert.Equal(t, "a.b", joinFieldPath("a", "b"))
+	assert.Equal(t, `a["b.c"]`, joinFieldPath("a", "b.c"))
+	assert.Equal(t, `[""]`, joinFieldPath("", ""))
+}
@@ -0,0 +1,234 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+// the minimum number of slots allocated for a deque
+const minimumDequeCapacity = 8
+
+//
+// A double-ended queue backed by a growable ring buffer.
+// Elements can be added and removed at both ends in amortized
+// constant time, and accessed by their index from the front.
+// The deque is not safe for concurrent use.
+//
+type Deque[T any] struct {
+	buffer []T
+	head   int
+	count  int
+}
+
+//
+// Create a new empty deque with room for at least `capacity`
+// elements before it needs to grow.
+//
+func NewDeque[T any](capacity int) *Deque[T] {
+	if capacity < minimumDequeCapacity {
+		capacity = minimumDequeCapacity
+	}
+
+	return &Deque[T]{
+		buffer: make([]T, capacity),
+	}
+}
+
+//
+// Return the number of elements in the deque.
+//
+func (deque *Deque[T]) Len() int {
+	return deque.count
+}
+
+//
+// Check if the deque has no elements.
+//
+func (deque *Deque[T]) IsEmpty() bool {
+	return deque.count == 0
+}
+
+// physical position in the buffer for the logical index
+func (deque *Deque[T]) position(index int) int {
+	return (deque.head + index) % len(deque.buffer)
+}
+
+// double the buffer when it is full
+func (deque *Deque[T]) grow() {
+	if deque.count < len(deque.buffer) {
+		return
+	}
+
+	size := len(deque.buffer) * 2
+	if size < minimumDequeCapacity {
+		size = minimumDequeCapacity
+	}
+
+	buffer := make([]T, size)
+	for index := 0; index < deque.count; index++ {
+		buffer[index] = deque.buffer[deque.position(index)]
+	}
+
+	deque.buffer = buffer
+	deque.head = 0
+}
+
+//
+// Add the value at the front of the deque.
+//
+func (deque *Deque[T]) PushFront(value T) {
+	deque.grow()
+	deque.head = (deque.head - 1 + len(deque.buffer)) % len(deque.buffer)
+	deque.buffer[deque.head] = value
+	deque.count++
+}
+
+//
+// Add the value at the back of the deque.
+//
+func (deque *Deque[T]) PushBack(value T) {
+	deque.grow()
+	deque.buffer[deque.position(deque.count)] = value
+	deque.count++
+}
+
+//
+// Remove and return the value at the front of the deque.
+// Returns `false` if the deque is empty.
+//
+func (deque *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if deque.count == 0 {
+		return zero, false
+	}
+
+	value := deque.buffer[deque.head]
+	deque.buffer[deque.head] = zero
+	deque.head = deque.position(1)
+	deque.count--
+	return value, true
+}
+
+//
+// Remove and return the value at the back of the deque.
+// Returns `false` if the deque is empty.
+//
+func (deque *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if deque.count == 0 {
+		return zero, false
+	}
+
+	position := deque.position(deque.count - 1)
+	value := deque.buffer[position]
+	deque.buffer[position] = zero
+	deque.count--
+	return value, true
+}
+
+//
+// Return the value at the front of the deque without
+// removing it. Returns `false` if the deque is empty.
+//
+func (deque *Deque[T]) PeekFront() (T, bool) {
+	return deque.Get(0)
+}
+
+//
+// Return the value at the back of the deque without
+// removing it. Returns `false` if the deque is empty.
+//
+func (deque *Deque[T]) PeekBack() (T, bool) {
+	return deque.Get(deque.count - 1)
+}
+
+//
+// Return the value at the given index counted from the front.
+// Returns `false` if the index is out of range.
+//
+func (deque *Deque[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= deque.count {
+		var zero T
+		return zero, false
+	}
+
+	return deque.buffer[deque.position(index)], true
+}
+
+//
+// Replace the value at the given index counted from the front.
+// Returns `false` if the index is out of range.
+//
+func (deque *Deque[T]) Set(index int, value T) bool {
+	if index < 0 || index >= deque.count {
+		return false
+	}
+
+	deque.buffer[deque.position(index)] = value
+	return true
+}
+
+//
+// Rotate the deque by `steps` to the right, so that the last
+// `steps` elements move to the front. A negative value rotates
+// to the left.
+//
+func (deque *Deque[T]) Rotate(steps int) {
+	if deque.count <= 1 {
+		return
+	}
+
+	steps %= deque.count
+	if steps < 0 {
+		steps += deque.count
+	}
+
+	for ; steps > 0; steps-- {
+		value, _ := deque.PopBack()
+		deque.PushFront(value)
+	}
+}
+
+//
+// Remove all elements from the deque.
+//
+func (deque *Deque[T]) Clear() {
+	var zero T
+	for index := 0; index < deque.count; index++ {
+		deque.buffer[deque.position(index)] = zero
+	}
+
+	deque.head = 0
+	deque.count = 0
+}
+
+//
+// Return an iterator over the elements from front to back.
+// The deque must not be modified while iterating.
+//
+func (deque *Deque[T]) Iterator() Iterator[T] {
+	index := 0
+	return IteratorFunc[T](func() (T, bool) {
+		value, ok := deque.Get(index)
+		index++
+		return value, ok
+	})
+}
+
+//
+// Return a copy of the elements from front to back.
+//
+func (deque *Deque[T]) ToSlice() []T {
+	result := make([]T, deque.count)
+	for index := 0; index < deque.count; index++ {
+		result[index] = deque.buffer[deque.position(index)]
+	}
+
+	return result
+}
@@ -0,0 +1,118 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeque(t *testing.T) {
+	deque := NewDeque[int](0)
+	assert.True(t, deque.IsEmpty())
+
+	_, ok := deque.PopFront()
+	assert.False(t, ok)
+	_, ok = deque.PopBack()
+	assert.False(t, ok)
+	_, ok = deque.PeekFront()
+	assert.False(t, ok)
+	_, ok = deque.PeekBack()
+	assert.False(t, ok)
+
+	deque.PushBack(2)
+	deque.PushBack(3)
+	deque.PushFront(1)
+	deque.PushFront(0)
+	assert.Equal(t, 4, deque.Len())
+	assert.Equal(t, []int{0, 1, 2, 3}, deque.ToSlice())
+
+	value, _ := deque.PeekFront()
+	assert.Equal(t, 0, value)
+	value, _ = deque.PeekBack()
+	assert.Equal(t, 3, value)
+
+	value, ok = deque.PopFront()
+	assert.True(t, ok)
+	assert.Equal(t, 0, value)
+	value, ok = deque.PopBack()
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+	assert.Equal(t, []int{1, 2}, deque.ToSlice())
+
+	// index access
+	value, ok = deque.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	_, ok = deque.Get(2)
+	assert.False(t, ok)
+	_, ok = deque.Get(-1)
+	assert.False(t, ok)
+
+	assert.True(t, deque.Set(0, 10))
+	assert.False(t, deque.Set(5, 10))
+	assert.Equal(t, []int{10, 2}, deque.ToSlice())
+
+	deque.Clear()
+	assert.True(t, deque.IsEmpty())
+	assert.Equal(t, []int{}, deque.ToSlice())
+}
+
+func TestDequeGrow(t *testing.T) {
+	deque := NewDeque[int](8)
+	expected := make([]int, 0)
+
+	// wrap around the buffer before growing
+	for index := 0; index < 5; index++ {
+		deque.PushBack(index)
+	}
+	for index := 0; index < 5; index++ {
+		deque.PopFront()
+	}
+
+	for index := 0; index < 50; index++ {
+		if index%2 == 0 {
+			deque.PushBack(index)
+			expected = append(expected, index)
+		} else {
+			deque.PushFront(index)
+			expected = append([]int{index}, expected...)
+		}
+	}
+
+	assert.Equal(t, 50, deque.Len())
+	assert.Equal(t, expected, deque.ToSlice())
+	assert.Equal(t, expected, NewStream(deque.Iterator()).Collect())
+}
+
+func TestDequeRotate(t *testing.T) {
+	deque := NewDeque[int](4)
+	deque.Rotate(3)
+	assert.Equal(t, []int{}, deque.ToSlice())
+
+	for index := 1; index <= 5; index++ {
+		deque.PushBack(index)
+	}
+
+	deque.Rotate(2)
+	assert.Equal(t, []int{4, 5, 1, 2, 3}, deque.ToSlice())
+
+	deque.Rotate(-2)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, deque.ToSlice())
+
+	deque.Rotate(-6)
+	assert.Equal(t, []int{2, 3, 4, 5, 1}, deque.ToSlice())
+
+	deque.Rotate(10)
+	assert.Equal(t, []int{2, 3, 4, 5, 1}, deque.ToSlice())
+}
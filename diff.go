@@ -0,0 +1,410 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//
+// Defines the type of operation in an edit script produced
+// by `SliceDiff`.
+//
+type DiffOperation int
+
+const (
+	DiffEqual  DiffOperation = iota // element is present in both slices
+	DiffInsert                      // element was added in the updated slice
+	DiffDelete                      // element was removed from the original slice
+)
+
+//
+// Return the string representation of the operation.
+//
+func (op DiffOperation) String() string {
+	switch op {
+	case DiffEqual:
+		return "equal"
+
+	case DiffInsert:
+		return "insert"
+
+	case DiffDelete:
+		return "delete"
+	}
+
+	return "unknown"
+}
+
+//
+// A single operation in an edit script. `OldIndex` is the
+// index of the element in the original slice and is `-1` for
+// inserts. `NewIndex` is the index of the element in the
+// updated slice and is `-1` for deletes.
+//
+type DiffEdit[T comparable] struct {
+	Operation DiffOperation
+	OldIndex  int
+	NewIndex  int
+	Value     T
+}
+
+//
+// Compute the minimal edit script that converts the `original`
+// slice into the `updated` slice using the linear space variant
+// of the Myers diff algorithm, which takes O((N+M)·D) time and
+// O(N+M) memory for a script with D changes. The script contains
+// every element of both slices exactly once, in order, tagged as
+// equal, inserted or deleted; within a run of changes deletes
+// come before inserts. A `nil` slice is treated as an empty slice.
+//
+func SliceDiff[T comparable](original []T, updated []T) []DiffEdit[T] {
+	differ := &sliceDiffer[T]{
+		original: original,
+		updated:  updated,
+		edits:    make([]DiffEdit[T], 0, len(original)+len(updated)),
+	}
+
+	differ.diff(0, len(original), 0, len(updated))
+	differ.orderChanges()
+	return differ.edits
+}
+
+// holds the state of a single `SliceDiff` call
+type sliceDiffer[T comparable] struct {
+	original []T
+	updated  []T
+	edits    []DiffEdit[T]
+
+	// furthest reaching paths of the forward and backward searches
+	forward  []int
+	backward []int
+}
+
+// append the edits for the given ranges of both slices
+func (differ *sliceDiffer[T]) diff(oldStart int, oldEnd int, newStart int, newEnd int) {
+	// common prefix
+	for oldStart < oldEnd && newStart < newEnd && differ.original[oldStart] == differ.updated[newStart] {
+		differ.equal(oldStart, newStart)
+		oldStart++
+		newStart++
+	}
+
+	// common suffix, added after the changes
+	suffix := 0
+	for oldStart < oldEnd-suffix && newStart < newEnd-suffix && differ.original[oldEnd-suffix-1] == differ.updated[newEnd-suffix-1] {
+		suffix++
+	}
+	oldEnd -= suffix
+	newEnd -= suffix
+
+	switch {
+	case oldStart == oldEnd:
+		for index := newStart; index < newEnd; index++ {
+			differ.edits = append(differ.edits, DiffEdit[T]{Operation: DiffInsert, OldIndex: -1, NewIndex: index, Value: differ.updated[index]})
+		}
+
+	case newStart == newEnd:
+		for index := oldStart; index < oldEnd; index++ {
+			differ.edits = append(differ.edits, DiffEdit[T]{Operation: DiffDelete, OldIndex: index, NewIndex: -1, Value: differ.original[index]})
+		}
+
+	default:
+		// both ranges differ at their ends, so the script has at
+		// least two changes and the snake splits it into two
+		// smaller problems
+		x, y, u, v := differ.middleSnake(oldStart, oldEnd, newStart, newEnd)
+		differ.diff(oldStart, x, newStart, y)
+		for ; x < u; x, y = x+1, y+1 {
+			differ.equal(x, y)
+		}
+		differ.diff(u, oldEnd, v, newEnd)
+	}
+
+	for index := 0; index < suffix; index++ {
+		differ.equal(oldEnd+index, newEnd+index)
+	}
+}
+
+// append an equal edit
+func (differ *sliceDiffer[T]) equal(oldIndex int, newIndex int) {
+	differ.edits = append(differ.edits, DiffEdit[T]{Operation: DiffEqual, OldIndex: oldIndex, NewIndex: newIndex, Value: differ.original[oldIndex]})
+}
+
+// find the snake in the middle of a shortest edit script for the
+// given ranges by searching from both ends at once, returning
+// the start and end of the snake in slice indexes
+func (differ *sliceDiffer[T]) middleSnake(oldStart int, oldEnd int, newStart int, newEnd int) (int, int, int, int) {
+	original := differ.original[oldStart:oldEnd]
+	updated := differ.updated[newStart:newEnd]
+	n := len(original)
+	m := len(updated)
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+
+	// the paths are stored at `offset + diagonal`, the backward
+	// path counts the elements consumed from the ends
+	offset := max + 1
+	size := 2*max + 3
+	if cap(differ.forward) < size {
+		differ.forward = make([]int, size)
+		differ.backward = make([]int, size)
+	}
+	forward := differ.forward[:size]
+	backward := differ.backward[:size]
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			startX := x
+			y := x - k
+			for x < n && y < m && original[x] == updated[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// overlaps the backward path of the previous round
+			c := delta - k
+			if odd && c >= -(d-1) && c <= d-1 && x+backward[offset+c] >= n {
+				return oldStart + startX, newStart + startX - k, oldStart + x, newStart + y
+			}
+		}
+
+		for c := -d; c <= d; c += 2 {
+			var x int
+			if c == -d || (c != d && backward[offset+c-1] < backward[offset+c+1]) {
+				x = backward[offset+c+1]
+			} else {
+				x = backward[offset+c-1] + 1
+			}
+
+			startX := x
+			y := x - c
+			for x < n && y < m && original[n-x-1] == updated[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+c] = x
+
+			// overlaps the forward path of this round
+			k := delta - c
+			if !odd && k >= -d && k <= d && forward[offset+k]+x >= n {
+				return oldStart + n - x, newStart + m - y, oldStart + n - startX, newStart + m - startX + c
+			}
+		}
+	}
+
+	// not reached, the searches always meet
+	return oldStart, newStart, oldStart, newStart
+}
+
+// move the deletes before the inserts in every run of changes
+func (differ *sliceDiffer[T]) orderChanges() {
+	edits := differ.edits
+	for start := 0; start < len(edits); {
+		if edits[start].Operation == DiffEqual {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(edits) && edits[end].Operation != DiffEqual {
+			end++
+		}
+
+		sort.SliceStable(edits[start:end], func(i, j int) bool {
+			return edits[start+i].Operation == DiffDelete && edits[start+j].Operation == DiffInsert
+		})
+		start = end
+	}
+}
+
+//
+// Check if the edit script contains any insert or delete
+// operation. Returns `false` if the script is `nil` or empty.
+//
+func DiffHasChanges[T comparable](edits []DiffEdit[T]) bool {
+	for _, edit := range edits {
+		if edit.Operation != DiffEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Apply the edit script on the `original` slice and return
+// the updated slice. The original slice is not modified. Returns
+// an `error` if the script does not match the original slice,
+// that is, if an equal or delete operation refers to an element
+// that is not present at the given index.
+//
+func SliceDiffPatch[T comparable](original []T, edits []DiffEdit[T]) ([]T, error) {
+	result := make([]T, 0, len(original))
+	position := 0
+
+	for _, edit := range edits {
+		switch edit.Operation {
+		case DiffEqual, DiffDelete:
+			if edit.OldIndex != position {
+				return nil, fmt.Errorf("Edit script is out of order at original index %d", edit.OldIndex)
+			}
+
+			if position >= len(original) || original[position] != edit.Value {
+				return nil, fmt.Errorf("Edit script does not match original slice at index %d", edit.OldIndex)
+			}
+
+			if edit.Operation == DiffEqual {
+				result = append(result, edit.Value)
+			}
+			position++
+
+		case DiffInsert:
+			result = append(result, edit.Value)
+
+		default:
+			return nil, errors.New("Unknown operation in edit script")
+		}
+	}
+
+	if position != len(original) {
+		return nil, errors.New("Edit script does not cover the complete original slice")
+	}
+
+	return result, nil
+}
+
+//
+// Render the edit script for string slices in the unified diff
+// format. `fromName` and `toName` are written in the file headers
+// and are omitted if both are empty. `contextLines` is the number
+// of unchanged lines shown around each change. Returns an empty
+// string if the script has no changes.
+//
+func RenderUnifiedDiff(fromName string, toName string, edits []DiffEdit[string], contextLines int) string {
+	if !DiffHasChanges(edits) {
+		return ""
+	}
+
+	if contextLines < 0 {
+		contextLines = 0
+	}
+
+	// number of original and updated lines consumed before each edit
+	oldPos := make([]int, len(edits)+1)
+	newPos := make([]int, len(edits)+1)
+	for index, edit := range edits {
+		oldPos[index+1] = oldPos[index]
+		newPos[index+1] = newPos[index]
+		if edit.Operation != DiffInsert {
+			oldPos[index+1]++
+		}
+		if edit.Operation != DiffDelete {
+			newPos[index+1]++
+		}
+	}
+
+	var builder strings.Builder
+	if fromName != "" || toName != "" {
+		builder.WriteString("--- " + fromName + "\n")
+		builder.WriteString("+++ " + toName + "\n")
+	}
+
+	index := 0
+	for index < len(edits) {
+		// find the next change
+		for index < len(edits) && edits[index].Operation == DiffEqual {
+			index++
+		}
+		if index == len(edits) {
+			break
+		}
+
+		start := index - contextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// extend the hunk while changes are close enough to share context
+		end := index
+		for end < len(edits) {
+			if edits[end].Operation != DiffEqual {
+				end++
+				continue
+			}
+
+			run := end
+			for run < len(edits) && edits[run].Operation == DiffEqual {
+				run++
+			}
+
+			if run == len(edits) || run-end > 2*contextLines {
+				break
+			}
+			end = run
+		}
+
+		stop := end + contextLines
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+
+		oldCount := oldPos[stop] - oldPos[start]
+		newCount := newPos[stop] - newPos[start]
+		builder.WriteString("@@ -" + unifiedRange(oldPos[start], oldCount) + " +" + unifiedRange(newPos[start], newCount) + " @@\n")
+
+		for _, edit := range edits[start:stop] {
+			switch edit.Operation {
+			case DiffEqual:
+				builder.WriteString(" ")
+			case DiffInsert:
+				builder.WriteString("+")
+			case DiffDelete:
+				builder.WriteString("-")
+			}
+
+			builder.WriteString(edit.Value)
+			builder.WriteString("\n")
+		}
+
+		index = stop
+	}
+
+	return builder.String()
+}
+
+// format a hunk range as per unified diff conventions
+func unifiedRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
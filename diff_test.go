@@ -0,0 +1,180 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSliceDiff(t *testing.T) {
+	// both empty
+	assert.Equal(t, 0, len(SliceDiff[int](nil, nil)))
+
+	// all inserts
+	edits := SliceDiff(nil, []int{1, 2})
+	assert.Equal(t, []DiffEdit[int]{
+		{Operation: DiffInsert, OldIndex: -1, NewIndex: 0, Value: 1},
+		{Operation: DiffInsert, OldIndex: -1, NewIndex: 1, Value: 2},
+	}, edits)
+
+	// all deletes
+	edits = SliceDiff([]int{1, 2}, []int{})
+	assert.Equal(t, []DiffEdit[int]{
+		{Operation: DiffDelete, OldIndex: 0, NewIndex: -1, Value: 1},
+		{Operation: DiffDelete, OldIndex: 1, NewIndex: -1, Value: 2},
+	}, edits)
+
+	// equal
+	edits = SliceDiff([]int{1, 2}, []int{1, 2})
+	assert.False(t, DiffHasChanges(edits))
+	assert.Equal(t, 2, len(edits))
+
+	// classic example from the Myers paper
+	original := []string{"A", "B", "C", "A", "B", "B", "A"}
+	updated := []string{"C", "B", "A", "B", "A", "C"}
+	stringEdits := SliceDiff(original, updated)
+	assert.True(t, DiffHasChanges(stringEdits))
+
+	changes := 0
+	for _, edit := range stringEdits {
+		if edit.Operation != DiffEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, 5, changes)
+
+	patched, err := SliceDiffPatch(original, stringEdits)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, patched)
+}
+
+func TestSliceDiffMinimal(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	for round := 0; round < 500; round++ {
+		original := make([]int, random.Intn(20))
+		for index := range original {
+			original[index] = random.Intn(4)
+		}
+		updated := make([]int, random.Intn(20))
+		for index := range updated {
+			updated[index] = random.Intn(4)
+		}
+
+		edits := SliceDiff(original, updated)
+		equal := 0
+		for _, edit := range edits {
+			if edit.Operation == DiffEqual {
+				equal++
+			}
+		}
+		assert.Equal(t, longestCommonSubsequence(original, updated), equal)
+
+		patched, err := SliceDiffPatch(original, edits)
+		assert.NoError(t, err)
+		assert.Equal(t, len(updated), len(patched))
+		if len(updated) > 0 {
+			assert.Equal(t, updated, patched)
+		}
+	}
+}
+
+func TestSliceDiffMemory(t *testing.T) {
+	original := make([]int, 3000)
+	updated := make([]int, 3000)
+	for index := range original {
+		original[index] = index
+		updated[index] = -index - 1
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	edits := SliceDiff(original, updated)
+	runtime.ReadMemStats(&after)
+
+	assert.Equal(t, 6000, len(edits))
+	assert.Equal(t, DiffDelete, edits[0].Operation)
+	assert.Equal(t, DiffInsert, edits[5999].Operation)
+
+	// the script itself takes about 200 KB
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+}
+
+func longestCommonSubsequence(original []int, updated []int) int {
+	lengths := make([][]int, len(original)+1)
+	for index := range lengths {
+		lengths[index] = make([]int, len(updated)+1)
+	}
+
+	for i := 1; i <= len(original); i++ {
+		for j := 1; j <= len(updated); j++ {
+			switch {
+			case original[i-1] == updated[j-1]:
+				lengths[i][j] = lengths[i-1][j-1] + 1
+			case lengths[i-1][j] > lengths[i][j-1]:
+				lengths[i][j] = lengths[i-1][j]
+			default:
+				lengths[i][j] = lengths[i][j-1]
+			}
+		}
+	}
+
+	return lengths[len(original)][len(updated)]
+}
+
+func TestSliceDiffPatch(t *testing.T) {
+	original := []int{1, 2, 3, 4, 5}
+	updated := []int{1, 3, 4, 6, 5, 7}
+
+	edits := SliceDiff(original, updated)
+	patched, err := SliceDiffPatch(original, edits)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, patched)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, original)
+
+	// script does not match
+	_, err = SliceDiffPatch([]int{9, 2, 3, 4, 5}, edits)
+	assert.Error(t, err)
+
+	// script does not cover the original
+	_, err = SliceDiffPatch([]int{1, 2, 3, 4, 5, 8}, edits)
+	assert.Error(t, err)
+}
+
+func TestRenderUnifiedDiff(t *testing.T) {
+	// no changes
+	edits := SliceDiff([]string{"a"}, []string{"a"})
+	assert.Equal(t, "", RenderUnifiedDiff("a.txt", "b.txt", edits, 3))
+
+	original := []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+	updated := []string{"one", "2", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven"}
+	edits = SliceDiff(original, updated)
+
+	expected := "--- a.txt\n" +
+		"+++ b.txt\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" one\n" +
+		"-two\n" +
+		"+2\n" +
+		" three\n" +
+		"@@ -10 +10,2 @@\n" +
+		" ten\n" +
+		"+eleven\n"
+	assert.Equal(t, expected, RenderUnifiedDiff("a.txt", "b.txt", edits, 1))
+
+	// large context merges hunks, no headers
+	text := RenderUnifiedDiff("", "", edits, 5)
+	assert.Equal(t, "@@ -1,10 +1,11 @@\n", text[:len("@@ -1,10 +1,11 @@\n")])
+}
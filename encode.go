@@ -0,0 +1,374 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+//
+// Returned by `EncodeMap` when the value is not a struct or a
+// non-nil pointer to a struct.
+//
+var ErrInvalidEncodeValue = errors.New("Value must be a struct or a pointer to a struct")
+
+//
+// Returned by `EncodeMap`, in a `*FieldError`, when a pointer,
+// map or slice refers back to a value that contains it.
+//
+var ErrEncodeCycle = errors.New("Value refers to itself")
+
+//
+// Implemented by types that provide their own value when encoded
+// by `EncodeMap`. The returned value is placed in the map as is.
+//
+type MapValueMarshaler interface {
+	MarshalMapValue() (interface{}, error)
+}
+
+//
+// Options that control how `EncodeMap` reads struct fields.
+// A `nil` options value uses the defaults.
+//
+type EncodeOptions struct {
+	TagName string // struct tag to read field names and options from, defaults to `berry`
+}
+
+// return the struct tag to read
+func (options *EncodeOptions) tagName() string {
+	if options == nil || options.TagName == "" {
+		return "berry"
+	}
+
+	return options.TagName
+}
+
+//
+// Encode the struct, or the struct that `value` points to, into
+// nested `map[string]interface{}` values that can be used with
+// the path, flatten and conversion functions, and read back with
+// `DecodeMap`. Each exported field is written to the key named in
+// its `berry` tag, or the tag set in the options such as `json`,
+// or to its name. A tag of `-` skips the field and `omitempty`
+// skips it when it is `false`, zero, `nil` or empty. Fields of
+// embedded structs without a tag name are written to the same
+// map unless the outer struct has a field with the same name.
+// Nested structs become maps, slices and arrays other than
+// `[]byte` become `[]interface{}`, and maps become maps with the
+// keys converted by `ConvertToString`. Values implementing
+// `MapValueMarshaler`, `json.Marshaler` or
+// `encoding.TextMarshaler` are encoded with the first of these
+// methods they have; other values are kept as is. Returns
+// `ErrInvalidEncodeValue` if the value is not a struct, or
+// `FieldErrors` listing every field whose marshaler failed or
+// that refers back to a value containing it, which is reported
+// as `ErrEncodeCycle` rather than followed forever.
+//
+func EncodeMap(value interface{}, options *EncodeOptions) (map[string]interface{}, error) {
+	encoder := &mapEncoder{
+		tagName:  options.tagName(),
+		visiting: make(map[encodeVisit]bool),
+	}
+
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() == reflect.Ptr && !reflected.IsNil() {
+		encoder.enter(reflected, "")
+		reflected = reflected.Elem()
+	}
+
+	if reflected.Kind() != reflect.Struct {
+		return nil, ErrInvalidEncodeValue
+	}
+
+	// copy structs passed by value so that methods with a
+	// pointer receiver can be called on their fields
+	if !reflected.CanAddr() {
+		addressable := reflect.New(reflected.Type()).Elem()
+		addressable.Set(reflected)
+		reflected = addressable
+	}
+
+	result := encoder.encodeFields(reflected, "", nil)
+	if len(encoder.errs) > 0 {
+		return nil, encoder.errs
+	}
+
+	return result, nil
+}
+
+var mapValueMarshalerType = reflect.TypeOf((*MapValueMarshaler)(nil)).Elem()
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// holds the state of a single `EncodeMap` call
+type mapEncoder struct {
+	tagName string
+	errs    FieldErrors
+
+	// the pointers, maps and slices being encoded on the path
+	// to the current value
+	visiting map[encodeVisit]bool
+}
+
+// identifies a pointer, map or slice; the type tells a struct
+// apart from its first field at the same address
+type encodeVisit struct {
+	pointer   uintptr
+	length    int
+	valueType reflect.Type
+}
+
+// mark the pointer, map or slice as being encoded, returning
+// `false` and recording an error if it already is
+func (encoder *mapEncoder) enter(value reflect.Value, path string) bool {
+	visit := visitOf(value)
+	if encoder.visiting[visit] {
+		encoder.errs = append(encoder.errs, &FieldError{Path: path, Err: ErrEncodeCycle})
+		return false
+	}
+
+	encoder.visiting[visit] = true
+	return true
+}
+
+// mark the pointer, map or slice as encoded
+func (encoder *mapEncoder) leave(value reflect.Value) {
+	delete(encoder.visiting, visitOf(value))
+}
+
+// identify the pointer, map or slice
+func visitOf(value reflect.Value) encodeVisit {
+	visit := encodeVisit{pointer: value.Pointer(), valueType: value.Type()}
+	if value.Kind() == reflect.Slice {
+		visit.length = value.Len()
+	}
+
+	return visit
+}
+
+// encode the fields of the struct into a new map. Fields named
+// in `hidden` are shadowed by an outer struct.
+func (encoder *mapEncoder) encodeFields(value reflect.Value, path string, hidden map[string]bool) map[string]interface{} {
+	result := make(map[string]interface{})
+	var embedded []map[string]interface{}
+
+	structType := value.Type()
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag := parseFieldTag(field.Tag.Get(encoder.tagName))
+		if tag.skip {
+			continue
+		}
+
+		fieldValue := value.Field(index)
+		if isInlinedField(field, tag) {
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+
+			embeddedHidden := mergeFieldNames(hidden, directFieldNames(structType, encoder.tagName))
+			embedded = append(embedded, encoder.encodeFields(fieldValue, path, embeddedHidden))
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := tag.name
+		if name == "" {
+			name = field.Name
+		}
+
+		if hidden[name] || (tag.omitEmpty && isEmptyValue(fieldValue)) {
+			continue
+		}
+
+		result[name] = encoder.encodeValue(fieldValue, joinFieldPath(path, name))
+	}
+
+	// the first embedded struct wins if several have a field
+	for _, inlined := range embedded {
+		for key, entry := range inlined {
+			if _, found := result[key]; !found {
+				result[key] = entry
+			}
+		}
+	}
+
+	return result
+}
+
+// encode the value for the map, recording any errors
+func (encoder *mapEncoder) encodeValue(value reflect.Value, path string) interface{} {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if value.IsNil() || (value.Kind() == reflect.Slice && value.Len() == 0) {
+			break
+		}
+
+		if !encoder.enter(value, path) {
+			return nil
+		}
+		defer encoder.leave(value)
+	}
+
+	if encoded, ok := encoder.encodeMarshaler(value, path); ok {
+		return encoded
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encoder.encodeValue(value.Elem(), path)
+
+	case reflect.Struct:
+		return encoder.encodeFields(value, path, nil)
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice {
+			if value.IsNil() {
+				return nil
+			}
+
+			if value.Type().Elem().Kind() == reflect.Uint8 {
+				return value.Interface()
+			}
+		}
+
+		items := make([]interface{}, value.Len())
+		for index := range items {
+			items[index] = encoder.encodeValue(value.Index(index), path+"["+strconv.Itoa(index)+"]")
+		}
+		return items
+
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+
+		entries := make(map[string]interface{}, value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			key := ConvertToString(iterator.Key().Interface())
+			entries[key] = encoder.encodeValue(iterator.Value(), joinFieldPath(path, key))
+		}
+		return entries
+	}
+
+	if !value.CanInterface() {
+		return nil
+	}
+
+	return value.Interface()
+}
+
+// encode the value with its marshaler, returning `false` if it
+// has none
+func (encoder *mapEncoder) encodeMarshaler(value reflect.Value, path string) (interface{}, bool) {
+	marshaler, ok := marshalerOf(value)
+	if !ok {
+		return nil, false
+	}
+
+	var encoded interface{}
+	var err error
+
+	switch m := marshaler.(type) {
+	case MapValueMarshaler:
+		encoded, err = m.MarshalMapValue()
+
+	case json.Marshaler:
+		var data []byte
+		if data, err = m.MarshalJSON(); err == nil {
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.UseNumber()
+			err = decoder.Decode(&encoded)
+		}
+
+	case encoding.TextMarshaler:
+		var text []byte
+		if text, err = m.MarshalText(); err == nil {
+			encoded = string(text)
+		}
+	}
+
+	if err != nil {
+		encoder.errs = append(encoder.errs, &FieldError{Path: path, Err: err})
+		return nil, true
+	}
+
+	return encoded, true
+}
+
+// return the value, or a pointer to it, as the first marshaler
+// it implements
+func marshalerOf(value reflect.Value) (interface{}, bool) {
+	if !value.CanInterface() {
+		return nil, false
+	}
+
+	candidates := []reflect.Value{value}
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		candidates = append(candidates, value.Addr())
+	}
+
+	for _, marshalerType := range []reflect.Type{mapValueMarshalerType, jsonMarshalerType, textMarshalerType} {
+		for _, candidate := range candidates {
+			if candidate.Type().Implements(marshalerType) {
+				return candidate.Interface(), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// check if the value is empty for `omitempty`, as defined by
+// `encoding/json`
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+
+	case reflect.Bool:
+		return !value.Bool()
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+
+	return false
+}
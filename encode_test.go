@@ -0,0 +1,341 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCelsius float64
+
+func (c testCelsius) MarshalMapValue() (interface{}, error) {
+	if c < -273.15 {
+		return nil, errors.New("Below absolute zero")
+	}
+
+	return map[string]interface{}{"celsius": float64(c), "fahrenheit": float64(c)*9/5 + 32}, nil
+}
+
+func (c *testCelsius) UnmarshalMapValue(value interface{}) error {
+	celsius, err := GetPathFloat64(value, "celsius", 0)
+	*c = testCelsius(celsius)
+	return err
+}
+
+type testPoint struct {
+	X, Y int
+}
+
+func (p *testPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+func (p *testPoint) UnmarshalJSON(data []byte) error {
+	var coordinates [2]int
+	if err := json.Unmarshal(data, &coordinates); err != nil {
+		return err
+	}
+
+	p.X, p.Y = coordinates[0], coordinates[1]
+	return nil
+}
+
+type testEncodeBase struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type testEncodeItem struct {
+	testEncodeBase
+	*testLimits
+
+	Name        string            `json:"title"`
+	Count       int               `json:"count,omitempty"`
+	Price       float64           `json:"price"`
+	Tags        []string          `json:"tags,omitempty"`
+	Attributes  map[string]string `json:"attributes"`
+	Parent      *testEncodeItem   `json:"parent,omitempty"`
+	Children    []testEncodeItem  `json:"children,omitempty"`
+	Created     time.Time         `json:"created"`
+	Address     net.IP            `json:"address"`
+	Temperature testCelsius       `json:"temperature"`
+	Location    testPoint         `json:"location"`
+	Extra       interface{}       `json:"extra"`
+	Scores      map[int][]int     `json:"scores,omitempty"`
+	Hidden      string            `json:"-"`
+
+	internal string
+}
+
+func TestEncodeMap(t *testing.T) {
+	item := testEncodeItem{
+		testEncodeBase: testEncodeBase{ID: "42", Name: "base"},
+		Name:           "widget",
+		Price:          9.5,
+		Tags:           []string{"a", "b"},
+		Attributes:     map[string]string{"color": "red"},
+		Parent:         &testEncodeItem{Name: "parent", Count: 1},
+		Created:        time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+		Address:        net.ParseIP("10.0.0.1"),
+		Temperature:    100,
+		Location:       testPoint{X: 1, Y: 2},
+		Extra:          &testEncodeBase{ID: "x"},
+		Scores:         map[int][]int{1: {2, 3}},
+		Hidden:         "hidden",
+		internal:       "internal",
+	}
+
+	encoded, err := EncodeMap(item, &EncodeOptions{TagName: "json"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":         "42",
+		"name":       "base",
+		"title":      "widget",
+		"price":      9.5,
+		"tags":       []interface{}{"a", "b"},
+		"attributes": map[string]interface{}{"color": "red"},
+		"parent": map[string]interface{}{
+			"id":          "",
+			"name":        "",
+			"title":       "parent",
+			"count":       1,
+			"price":       0.0,
+			"attributes":  nil,
+			"created":     "0001-01-01T00:00:00Z",
+			"address":     "",
+			"temperature": map[string]interface{}{"celsius": 0.0, "fahrenheit": 32.0},
+			"location":    []interface{}{json.Number("0"), json.Number("0")},
+			"extra":       nil,
+		},
+		"created":     "2022-03-04T05:06:07Z",
+		"address":     "10.0.0.1",
+		"temperature": map[string]interface{}{"celsius": 100.0, "fahrenheit": 212.0},
+		"location":    []interface{}{json.Number("1"), json.Number("2")},
+		"extra":       map[string]interface{}{"id": "x", "name": ""},
+		"scores":      map[string]interface{}{"1": []interface{}{2, 3}},
+	}, encoded)
+
+	// pointers are encoded as the struct
+	pointer, err := EncodeMap(&item, &EncodeOptions{TagName: "json"})
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, pointer)
+
+	// the encoded map works with the path and conversion functions
+	value, err := GetPath(encoded, "parent.title")
+	assert.NoError(t, err)
+	assert.Equal(t, "parent", value)
+	assert.Equal(t, "9.5", ConvertToString(encoded["price"]))
+	assert.Equal(t, "red", Flatten(encoded, nil)["attributes.color"])
+}
+
+func TestEncodeMapDefaultTag(t *testing.T) {
+	type plain struct {
+		Name    string
+		Port    int    `berry:"port"`
+		Skipped string `berry:"-"`
+		Empty   []int  `berry:",omitempty"`
+		Point   *testPoint
+	}
+
+	encoded, err := EncodeMap(plain{Name: "api", Port: 80, Skipped: "x"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": "api", "port": 80, "Point": nil}, encoded)
+
+	// embedded pointers are inlined when set
+	encoded, err = EncodeMap(testEncodeItem{testLimits: &testLimits{MaxConnections: 3}}, &EncodeOptions{TagName: "json"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, encoded["MaxConnections"])
+}
+
+func TestEncodeMapErrors(t *testing.T) {
+	number := 5
+	var missing *testEncodeItem
+
+	for _, value := range []interface{}{nil, number, &number, missing, []testEncodeItem{}} {
+		_, err := EncodeMap(value, nil)
+		assert.ErrorIs(t, err, ErrInvalidEncodeValue)
+	}
+
+	item := testEncodeItem{
+		Temperature: -300,
+		Children:    []testEncodeItem{{}, {Temperature: -500}},
+	}
+
+	_, err := EncodeMap(item, &EncodeOptions{TagName: "json"})
+
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, 2, len(fieldErrors))
+	assert.Equal(t, "children[1].temperature", fieldErrors[0].Path)
+	assert.Equal(t, "Field temperature: Below absolute zero", fieldErrors[1].Error())
+}
+
+type testEncodeNode struct {
+	Name     string                 `berry:"name"`
+	Next     *testEncodeNode        `berry:"next"`
+	Children []*testEncodeNode      `berry:"children"`
+	Extra    map[string]interface{} `berry:"extra"`
+}
+
+func TestEncodeMapCycles(t *testing.T) {
+	var fieldErrors FieldErrors
+
+	// a node pointing at itself
+	node := &testEncodeNode{Name: "a"}
+	node.Next = node
+	_, err := EncodeMap(node, nil)
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, 1, len(fieldErrors))
+	assert.Equal(t, "next", fieldErrors[0].Path)
+	assert.ErrorIs(t, fieldErrors[0], ErrEncodeCycle)
+
+	// a longer cycle through a slice
+	other := &testEncodeNode{Name: "b", Next: node}
+	node.Next = nil
+	node.Children = []*testEncodeNode{other}
+	_, err = EncodeMap(node, nil)
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, "children[0].next", fieldErrors[0].Path)
+
+	// a map holding itself
+	extra := map[string]interface{}{}
+	extra["self"] = extra
+	_, err = EncodeMap(testEncodeNode{Extra: extra}, nil)
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, "extra.self", fieldErrors[0].Path)
+	assert.ErrorIs(t, fieldErrors[0], ErrEncodeCycle)
+
+	// the same value in two places is not a cycle
+	shared := &testEncodeNode{Name: "shared"}
+	encoded, err := EncodeMap(testEncodeNode{Next: shared, Children: []*testEncodeNode{shared, shared}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", encoded["next"].(map[string]interface{})["name"])
+	assert.Equal(t, 2, len(encoded["children"].([]interface{})))
+}
+
+type testRoundTrip struct {
+	Name     string            `berry:"name"`
+	Port     uint16            `berry:"port"`
+	Enabled  bool              `berry:"enabled"`
+	Ratio    float64           `berry:"ratio"`
+	Timeout  time.Duration     `berry:"timeout"`
+	Created  time.Time         `berry:"created"`
+	Address  net.IP            `berry:"address"`
+	Tags     []string          `berry:"tags"`
+	Limits   map[string]int    `berry:"limits"`
+	Database testDatabase      `berry:"db"`
+	Backup   *testDatabase     `berry:"backup"`
+	Servers  []testDatabase    `berry:"servers"`
+	Labels   map[int]bool      `berry:"labels"`
+	Pair     [2]int            `berry:"pair"`
+	Payload  []byte            `berry:"payload"`
+	Nested   map[string][]int  `berry:"nested"`
+	Optional *string           `berry:"optional,omitempty"`
+	Meta     map[string]string `berry:"meta,omitempty"`
+	testAuditInfo
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	optional := "set"
+	original := testRoundTrip{
+		Name:     "api",
+		Port:     8080,
+		Enabled:  true,
+		Ratio:    0.25,
+		Timeout:  90 * time.Second,
+		Created:  time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+		Address:  net.ParseIP("10.0.0.1"),
+		Tags:     []string{"a", "b"},
+		Limits:   map[string]int{"cpu": 2},
+		Database: testDatabase{Host: "db", Port: 5432, Replicas: []string{"r1"}},
+		Backup:   &testDatabase{Host: "backup", Port: 1},
+		Servers:  []testDatabase{{Host: "one", Port: 1}, {Host: "two", Port: 2}},
+		Labels:   map[int]bool{1: true},
+		Pair:     [2]int{3, 4},
+		Payload:  []byte("data"),
+		Nested:   map[string][]int{"x": {1, 2}},
+		Optional: &optional,
+		testAuditInfo: testAuditInfo{
+			Owner: "ops",
+		},
+	}
+
+	encoded, err := EncodeMap(original, nil)
+	assert.NoError(t, err)
+
+	// the embedded field is shadowed by the outer one
+	shadowed := original
+	shadowed.testAuditInfo.Created = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	shadowedEncoded, err := EncodeMap(shadowed, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, shadowedEncoded)
+	assert.Equal(t, "2022-03-04T05:06:07Z", encoded["created"])
+
+	var decoded testRoundTrip
+	assert.NoError(t, DecodeMap(encoded, &decoded, nil))
+	assert.Equal(t, original, decoded)
+
+	// through flattened keys and back
+	flattenOptions := &FlattenOptions{IndexStyle: FlattenIndexBracket}
+	unflattened, err := Unflatten(Flatten(encoded, flattenOptions), flattenOptions)
+	assert.NoError(t, err)
+
+	decoded = testRoundTrip{}
+	assert.NoError(t, DecodeMap(unflattened, &decoded, nil))
+	assert.Equal(t, original, decoded)
+
+	// through JSON, where everything is a string, float or map
+	data, err := json.Marshal(encoded)
+	assert.NoError(t, err)
+
+	var parsed map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &parsed))
+
+	decoded = testRoundTrip{}
+	assert.NoError(t, DecodeMap(parsed, &decoded, nil))
+	original.Payload = nil
+	decoded.Payload = nil
+	assert.Equal(t, original, decoded)
+
+	// with json tags
+	item := testEncodeItem{
+		testEncodeBase: testEncodeBase{ID: "42", Name: "base"},
+		Name:           "widget",
+		Count:          3,
+		Temperature:    21.5,
+		Location:       testPoint{X: 5, Y: 6},
+		Tags:           []string{"a"},
+		Attributes:     map[string]string{"color": "red"},
+		Created:        time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+	}
+
+	encoded, err = EncodeMap(item, &EncodeOptions{TagName: "json"})
+	assert.NoError(t, err)
+
+	var decodedItem testEncodeItem
+	assert.NoError(t, DecodeMap(encoded, &decodedItem, &DecodeOptions{TagName: "json"}))
+	assert.Equal(t, item.testEncodeBase, decodedItem.testEncodeBase)
+	assert.Equal(t, item.Name, decodedItem.Name)
+	assert.Equal(t, item.Count, decodedItem.Count)
+	assert.Equal(t, item.Tags, decodedItem.Tags)
+	assert.Equal(t, item.Attributes, decodedItem.Attributes)
+	assert.Equal(t, item.Created, decodedItem.Created)
+	assert.Equal(t, item.Temperature, decodedItem.Temperature)
+	assert.Equal(t, item.Location, decodedItem.Location)
+}
@@ -17,6 +17,7 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type FileAsset struct {
@@ -32,6 +33,40 @@ type FileAsset struct {
 	Modified       int64  `json:"modified"`  // when was the file last modified
 }
 
+//
+// Return the size of the file in human readable form, like
+// `1.50 MiB`. See `FormatBytes`.
+//
+func (asset FileAsset) FormatSize(units ByteUnits, precision int) string {
+	return FormatBytes(asset.Size, units, precision)
+}
+
+//
+// Return the time the file was last modified in UTC, or the
+// zero time if it is not known. Unlike `ConvertToTime`, which
+// reads `0` as the Unix epoch, a `Modified` of `0` is unset.
+//
+func (asset FileAsset) ModifiedTime() time.Time {
+	return unixTime(asset.Modified)
+}
+
+//
+// Return the time the file was created in UTC, or the zero
+// time if it is not known, as for `ModifiedTime`.
+//
+func (asset FileAsset) CreatedTime() time.Time {
+	return unixTime(asset.Created)
+}
+
+// convert the Unix seconds to a time, keeping zero as unset
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(seconds, 0).UTC()
+}
+
 type FileFilter func(asset FileAsset) bool
 
 func ListFiles(path string, recursive bool) ([]*FileAsset, error) {
@@ -53,44 +88,17 @@ func listFilesInternal(path string, recursive bool, filter FileFilter) ([]*FileA
 		return nil, err
 	}
 
-	// create a dynamic array
-	fileLength := len(files)
-	var assetList []*FileAsset
-	if filter == nil {
-		assetList = make([]*FileAsset, fileLength)
-	} else {
-		assetList = make([]*FileAsset, 0, fileLength)
-	}
+	// create a dynamic array, always appended to as recursive
+	// listings add the assets of child folders in between
+	assetList := make([]*FileAsset, 0, len(files))
 
 	// populate the array
-	for index, file := range files {
-		extension := filepath.Ext(file.Name())
-
-		asset := FileAsset{
-			Id:             filepath.Join(path, file.Name()),
-			Name:           file.Name(),
-			Extension:      extension,
-			Path:           path,
-			Size:           uint64(file.Size()),
-			IsFolder:       file.IsDir(),
-			Modified:       file.ModTime().Unix(),
-			IsSymbolicLink: file.Mode()&os.ModeSymlink == os.ModeSymlink,
-			MimeType:       mime.TypeByExtension(extension),
-		}
+	for _, file := range files {
+		asset := newFileAsset(path, file)
 
-		// check for filter
-		if !recursive && filter == nil {
-			assetList[index] = &asset
-		} else {
-			// check if we have a filter
-			includeAsset := true
-			if filter != nil {
-				includeAsset = filter(asset)
-			}
-
-			if includeAsset {
-				assetList = append(assetList, &asset)
-			}
+		// check if we have a filter
+		if filter == nil || filter(asset) {
+			assetList = append(assetList, &asset)
 		}
 
 		// is this recursive mode?
@@ -108,6 +116,23 @@ func listFilesInternal(path string, recursive bool, filter FileFilter) ([]*FileA
 	return assetList, nil
 }
 
+// create the asset for a file read from the given folder path
+func newFileAsset(path string, file os.FileInfo) FileAsset {
+	extension := filepath.Ext(file.Name())
+
+	return FileAsset{
+		Id:             filepath.Join(path, file.Name()),
+		Name:           file.Name(),
+		Extension:      extension,
+		Path:           path,
+		Size:           uint64(file.Size()),
+		IsFolder:       file.IsDir(),
+		Modified:       file.ModTime().Unix(),
+		IsSymbolicLink: file.Mode()&os.ModeSymlink == os.ModeSymlink,
+		MimeType:       mime.TypeByExtension(extension),
+	}
+}
+
 //
 // Check if a given path exists or not?
 //
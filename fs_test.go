@@ -0,0 +1,71 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileAssetFormatSize(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "data.bin"), make([]byte, 1536), 0644))
+
+	files, err := ListFiles(dir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, "1.5 KiB", files[0].FormatSize(ByteUnitsIEC, 1))
+	assert.Equal(t, "1.54 kB", files[0].FormatSize(ByteUnitsSI, 2))
+}
+
+func TestFileAssetTimes(t *testing.T) {
+	asset := FileAsset{Modified: 1646370367}
+	assert.Equal(t, time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC), asset.ModifiedTime())
+	assert.True(t, asset.CreatedTime().IsZero())
+
+	converted, err := ConvertToTime(asset.Modified, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, converted, asset.ModifiedTime())
+
+	dir := t.TempDir()
+	before := time.Now().Add(-time.Minute)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "data.txt"), []byte("hello"), 0644))
+
+	files, err := ListFiles(dir, false)
+	assert.NoError(t, err)
+	assert.True(t, files[0].ModifiedTime().After(before))
+}
+
+func TestListFilesRecursive(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0644))
+
+	// recursive listings without a filter have no nil entries
+	files, err := ListFiles(dir, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(files))
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if assert.NotNil(t, file) {
+			names = append(names, file.Name)
+		}
+	}
+	assert.Equal(t, []string{"a.txt", "sub", "b.txt"}, names)
+	assert.Equal(t, filepath.Join(dir, "sub", "b.txt"), files[2].Id)
+}
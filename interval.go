@@ -0,0 +1,547 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//
+// A range of numbers between a lower and an upper bound, each
+// of which may be closed (included) or open (excluded). A range
+// whose lower bound is after its upper bound, or that excludes
+// its only point, is empty. Integer ranges are not normalized,
+// so `(1, 2)` is treated as non-empty.
+//
+type Range[T Number] struct {
+	Lower     T
+	Upper     T
+	LowerOpen bool
+	UpperOpen bool
+}
+
+//
+// Create the range `[lower, upper]` including both bounds.
+//
+func NewClosedRange[T Number](lower T, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper}
+}
+
+//
+// Create the range `(lower, upper)` excluding both bounds.
+//
+func NewOpenRange[T Number](lower T, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper, LowerOpen: true, UpperOpen: true}
+}
+
+//
+// Create the range `[lower, upper)` including the lower and
+// excluding the upper bound.
+//
+func NewClosedOpenRange[T Number](lower T, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper, UpperOpen: true}
+}
+
+//
+// Create the range `(lower, upper]` excluding the lower and
+// including the upper bound.
+//
+func NewOpenClosedRange[T Number](lower T, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper, LowerOpen: true}
+}
+
+//
+// Check if the range contains no numbers.
+//
+func (r Range[T]) IsEmpty() bool {
+	if r.Lower == r.Upper {
+		return r.LowerOpen || r.UpperOpen
+	}
+
+	return r.Lower > r.Upper
+}
+
+//
+// Check if the value lies within the range.
+//
+func (r Range[T]) Contains(value T) bool {
+	if value < r.Lower || (value == r.Lower && r.LowerOpen) {
+		return false
+	}
+
+	if value > r.Upper || (value == r.Upper && r.UpperOpen) {
+		return false
+	}
+
+	return true
+}
+
+//
+// Check if every number in the other range lies within this
+// range. An empty range is contained in every range.
+//
+func (r Range[T]) ContainsRange(other Range[T]) bool {
+	if other.IsEmpty() {
+		return true
+	}
+
+	if r.IsEmpty() {
+		return false
+	}
+
+	return compareLower(r, other) <= 0 && compareUpper(r, other) >= 0
+}
+
+//
+// Check if the two ranges have at least one number in common.
+//
+func (r Range[T]) Overlaps(other Range[T]) bool {
+	return !r.Intersection(other).IsEmpty()
+}
+
+//
+// Return the numbers common to both ranges. The result is an
+// empty range if they do not overlap.
+//
+func (r Range[T]) Intersection(other Range[T]) Range[T] {
+	result := r
+	if compareLower(other, r) > 0 {
+		result.Lower, result.LowerOpen = other.Lower, other.LowerOpen
+	}
+
+	if compareUpper(other, r) < 0 {
+		result.Upper, result.UpperOpen = other.Upper, other.UpperOpen
+	}
+
+	return result
+}
+
+//
+// Return the smallest range that contains both ranges. If one
+// of them is empty the other is returned.
+//
+func (r Range[T]) Span(other Range[T]) Range[T] {
+	if r.IsEmpty() {
+		return other
+	}
+
+	if other.IsEmpty() {
+		return r
+	}
+
+	result := r
+	if compareLower(other, r) < 0 {
+		result.Lower, result.LowerOpen = other.Lower, other.LowerOpen
+	}
+
+	if compareUpper(other, r) > 0 {
+		result.Upper, result.UpperOpen = other.Upper, other.UpperOpen
+	}
+
+	return result
+}
+
+//
+// Return the parts of this range that are not in the other
+// range. The result holds zero, one or two ranges in ascending
+// order.
+//
+func (r Range[T]) Subtract(other Range[T]) []Range[T] {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	if !r.Overlaps(other) {
+		return []Range[T]{r}
+	}
+
+	result := make([]Range[T], 0, 2)
+
+	before := Range[T]{Lower: r.Lower, LowerOpen: r.LowerOpen, Upper: other.Lower, UpperOpen: !other.LowerOpen}
+	if !before.IsEmpty() {
+		result = append(result, before)
+	}
+
+	after := Range[T]{Lower: other.Upper, LowerOpen: !other.UpperOpen, Upper: r.Upper, UpperOpen: r.UpperOpen}
+	if !after.IsEmpty() {
+		result = append(result, after)
+	}
+
+	return result
+}
+
+//
+// Return the distance between the bounds, or zero for an empty
+// range.
+//
+func (r Range[T]) Length() T {
+	if r.IsEmpty() {
+		return 0
+	}
+
+	return r.Upper - r.Lower
+}
+
+//
+// Return the range in interval notation such as `[1, 5)`.
+//
+func (r Range[T]) String() string {
+	open, close := "[", "]"
+	if r.LowerOpen {
+		open = "("
+	}
+	if r.UpperOpen {
+		close = ")"
+	}
+
+	return fmt.Sprintf("%s%v, %v%s", open, r.Lower, r.Upper, close)
+}
+
+// order ranges by their lower bound, a closed bound comes first
+func compareLower[T Number](a Range[T], b Range[T]) int {
+	switch {
+	case a.Lower < b.Lower:
+		return -1
+	case a.Lower > b.Lower:
+		return 1
+	case a.LowerOpen == b.LowerOpen:
+		return 0
+	case b.LowerOpen:
+		return -1
+	}
+
+	return 1
+}
+
+// order ranges by their upper bound, an open bound comes first
+func compareUpper[T Number](a Range[T], b Range[T]) int {
+	switch {
+	case a.Upper < b.Upper:
+		return -1
+	case a.Upper > b.Upper:
+		return 1
+	case a.UpperOpen == b.UpperOpen:
+		return 0
+	case a.UpperOpen:
+		return -1
+	}
+
+	return 1
+}
+
+// check if range a ends before range b begins, with no
+// number in common
+func endsBefore[T Number](a Range[T], b Range[T]) bool {
+	if a.Upper == b.Lower {
+		return a.UpperOpen || b.LowerOpen
+	}
+
+	return a.Upper < b.Lower
+}
+
+// check if range a ends before range b begins with a gap, so
+// that the two cannot be merged into one range
+func endsBeforeWithGap[T Number](a Range[T], b Range[T]) bool {
+	if a.Upper == b.Lower {
+		return a.UpperOpen && b.LowerOpen
+	}
+
+	return a.Upper < b.Lower
+}
+
+//
+// A set of numbers stored as non-overlapping ranges in ascending
+// order. Ranges that overlap or touch are merged when added. As
+// the ranges never overlap, a sorted slice searched by binary
+// search gives the same `O(log n)` point and range queries as an
+// interval tree. Adding or removing a single range shifts the
+// ranges after it and takes `O(n)` time for a set of `n` ranges;
+// `NewIntervalSet`, `Union` and `Subtract` work on many ranges
+// at once in `O(n log n)` time or better, and should be preferred
+// for bulk updates. The zero value is an empty set ready to use.
+// The set is not safe for concurrent use.
+//
+type IntervalSet[T Number] struct {
+	ranges []Range[T]
+}
+
+//
+// Create a new interval set holding the given ranges, in
+// `O(n log n)` time for `n` ranges.
+//
+func NewIntervalSet[T Number](ranges ...Range[T]) *IntervalSet[T] {
+	sorted := make([]Range[T], 0, len(ranges))
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+
+	return &IntervalSet[T]{ranges: mergeRanges(sorted)}
+}
+
+// sort the non-empty ranges and merge those that overlap or
+// touch, in place
+func mergeRanges[T Number](ranges []Range[T]) []Range[T] {
+	sort.Slice(ranges, func(i, j int) bool {
+		return compareLower(ranges[i], ranges[j]) < 0
+	})
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && !endsBeforeWithGap(merged[last], r) {
+			merged[last] = merged[last].Span(r)
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// return the index of the first range that does not end
+// before the given range begins
+func (set *IntervalSet[T]) search(r Range[T]) int {
+	return sort.Search(len(set.ranges), func(index int) bool {
+		return !endsBefore(set.ranges[index], r)
+	})
+}
+
+//
+// Add all numbers in the range to the set, merging it with
+// any ranges it overlaps or touches.
+//
+func (set *IntervalSet[T]) Add(r Range[T]) {
+	if r.IsEmpty() {
+		return
+	}
+
+	start := sort.Search(len(set.ranges), func(index int) bool {
+		return !endsBeforeWithGap(set.ranges[index], r)
+	})
+
+	end := start
+	for end < len(set.ranges) && !endsBeforeWithGap(r, set.ranges[end]) {
+		r = r.Span(set.ranges[end])
+		end++
+	}
+
+	set.splice(start, end, r)
+}
+
+// replace the ranges from start up to end with the given ranges
+func (set *IntervalSet[T]) splice(start int, end int, ranges ...Range[T]) {
+	length := len(set.ranges) - (end - start) + len(ranges)
+	if length > len(set.ranges) {
+		set.ranges = append(set.ranges, make([]Range[T], length-len(set.ranges))...)
+	}
+
+	copy(set.ranges[start+len(ranges):], set.ranges[end:])
+	copy(set.ranges[start:], ranges)
+	set.ranges = set.ranges[:length]
+}
+
+//
+// Remove all numbers in the range from the set.
+//
+func (set *IntervalSet[T]) Remove(r Range[T]) {
+	if r.IsEmpty() {
+		return
+	}
+
+	start := set.search(r)
+	end := start
+	for end < len(set.ranges) && !endsBefore(r, set.ranges[end]) {
+		end++
+	}
+
+	if start == end {
+		return
+	}
+
+	// the ranges in between are covered by the removed range,
+	// only the first and last may keep a part
+	kept := set.ranges[start].Subtract(r)
+	if end-start > 1 {
+		kept = append(kept, set.ranges[end-1].Subtract(r)...)
+	}
+
+	set.splice(start, end, kept...)
+}
+
+//
+// Add all numbers of the other set to this set, in place, in
+// `O((n+m) log(n+m))` time for sets of `n` and `m` ranges.
+//
+func (set *IntervalSet[T]) Union(other *IntervalSet[T]) *IntervalSet[T] {
+	ranges := make([]Range[T], 0, len(set.ranges)+len(other.ranges))
+	ranges = append(ranges, set.ranges...)
+	ranges = append(ranges, other.ranges...)
+	set.ranges = mergeRanges(ranges)
+	return set
+}
+
+//
+// Remove all numbers of the other set from this set, in place,
+// in `O(n+m)` time for sets of `n` and `m` ranges.
+//
+func (set *IntervalSet[T]) Subtract(other *IntervalSet[T]) *IntervalSet[T] {
+	ranges := make([]Range[T], 0, len(set.ranges))
+	first := 0
+	for _, current := range set.ranges {
+		for first < len(other.ranges) && endsBefore(other.ranges[first], current) {
+			first++
+		}
+
+		// remove the overlapping ranges in order, keeping what
+		// comes before each of them
+		rest, hasRest := current, true
+		for index := first; hasRest && index < len(other.ranges) && !endsBefore(rest, other.ranges[index]); index++ {
+			removed := other.ranges[index]
+			hasRest = false
+			for _, part := range rest.Subtract(removed) {
+				if endsBefore(part, removed) {
+					ranges = append(ranges, part)
+				} else {
+					rest, hasRest = part, true
+				}
+			}
+		}
+
+		if hasRest {
+			ranges = append(ranges, rest)
+		}
+	}
+
+	set.ranges = ranges
+	return set
+}
+
+//
+// Check if the value is in the set.
+//
+func (set *IntervalSet[T]) Contains(value T) bool {
+	point := NewClosedRange(value, value)
+	index := set.search(point)
+	return index < len(set.ranges) && set.ranges[index].Contains(value)
+}
+
+//
+// Check if every number in the range is in the set.
+//
+func (set *IntervalSet[T]) ContainsRange(r Range[T]) bool {
+	if r.IsEmpty() {
+		return true
+	}
+
+	index := set.search(r)
+	return index < len(set.ranges) && set.ranges[index].ContainsRange(r)
+}
+
+//
+// Check if any number in the range is in the set.
+//
+func (set *IntervalSet[T]) Overlaps(r Range[T]) bool {
+	index := set.search(r)
+	return index < len(set.ranges) && set.ranges[index].Overlaps(r)
+}
+
+//
+// Return the ranges of the set that overlap the given range,
+// in ascending order. Ranges are returned whole, not clipped.
+//
+func (set *IntervalSet[T]) Query(r Range[T]) []Range[T] {
+	result := make([]Range[T], 0)
+	if r.IsEmpty() {
+		return result
+	}
+
+	for index := set.search(r); index < len(set.ranges); index++ {
+		if !set.ranges[index].Overlaps(r) {
+			break
+		}
+
+		result = append(result, set.ranges[index])
+	}
+
+	return result
+}
+
+//
+// Return the smallest range containing every number in the
+// set, and `false` if the set is empty.
+//
+func (set *IntervalSet[T]) Bounds() (Range[T], bool) {
+	if len(set.ranges) == 0 {
+		return Range[T]{}, false
+	}
+
+	return set.ranges[0].Span(set.ranges[len(set.ranges)-1]), true
+}
+
+//
+// Return a copy of the ranges in ascending order.
+//
+func (set *IntervalSet[T]) Ranges() []Range[T] {
+	result := make([]Range[T], len(set.ranges))
+	copy(result, set.ranges)
+	return result
+}
+
+//
+// Return the number of separate ranges in the set.
+//
+func (set *IntervalSet[T]) Len() int {
+	return len(set.ranges)
+}
+
+//
+// Check if the set contains no numbers.
+//
+func (set *IntervalSet[T]) IsEmpty() bool {
+	return len(set.ranges) == 0
+}
+
+//
+// Return the sum of the lengths of all ranges.
+//
+func (set *IntervalSet[T]) TotalLength() T {
+	var total T
+	for _, r := range set.ranges {
+		total += r.Length()
+	}
+
+	return total
+}
+
+//
+// Remove all ranges from the set.
+//
+func (set *IntervalSet[T]) Clear() {
+	set.ranges = nil
+}
+
+//
+// Return the set as a list of ranges such as `{[1, 3], (5, 8)}`.
+//
+func (set *IntervalSet[T]) String() string {
+	parts := make([]string, len(set.ranges))
+	for index, r := range set.ranges {
+		parts[index] = r.String()
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
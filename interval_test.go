@@ -0,0 +1,222 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange(t *testing.T) {
+	closed := NewClosedRange(1, 5)
+	assert.Equal(t, "[1, 5]", closed.String())
+	assert.True(t, closed.Contains(1))
+	assert.True(t, closed.Contains(5))
+	assert.False(t, closed.Contains(6))
+	assert.Equal(t, 4, closed.Length())
+
+	open := NewOpenRange(1.0, 2.5)
+	assert.Equal(t, "(1, 2.5)", open.String())
+	assert.False(t, open.Contains(1))
+	assert.True(t, open.Contains(1.5))
+	assert.False(t, open.Contains(2.5))
+	assert.Equal(t, 1.5, open.Length())
+
+	assert.True(t, NewClosedOpenRange(1, 1).IsEmpty())
+	assert.True(t, NewClosedRange(3, 1).IsEmpty())
+	assert.False(t, NewClosedRange(1, 1).IsEmpty())
+	assert.Equal(t, 0, NewClosedRange(3, 1).Length())
+	assert.Equal(t, "(1, 2]", NewOpenClosedRange(1, 2).String())
+
+	assert.True(t, closed.ContainsRange(NewOpenRange(1, 5)))
+	assert.False(t, NewOpenRange(1, 5).ContainsRange(closed))
+	assert.True(t, closed.ContainsRange(NewClosedRange(9, 0)))
+	assert.False(t, NewClosedRange(9, 0).ContainsRange(closed))
+}
+
+func TestRangeOverlaps(t *testing.T) {
+	assert.True(t, NewClosedRange(1, 5).Overlaps(NewClosedRange(5, 8)))
+	assert.False(t, NewClosedOpenRange(1, 5).Overlaps(NewClosedRange(5, 8)))
+	assert.False(t, NewClosedRange(1, 5).Overlaps(NewOpenRange(5, 8)))
+	assert.False(t, NewClosedRange(1, 2).Overlaps(NewClosedRange(3, 4)))
+
+	assert.Equal(t, NewClosedOpenRange(3, 5), NewClosedOpenRange(1, 5).Intersection(NewClosedRange(3, 8)))
+	assert.Equal(t, NewOpenRange(1, 5), NewClosedRange(1, 5).Intersection(NewOpenRange(1, 5)))
+	assert.True(t, NewClosedRange(1, 2).Intersection(NewClosedRange(3, 4)).IsEmpty())
+
+	assert.Equal(t, NewClosedRange(1, 8), NewClosedOpenRange(1, 5).Span(NewOpenClosedRange(3, 8)))
+	assert.Equal(t, NewClosedRange(1, 5), NewOpenRange(1, 5).Span(NewClosedRange(1, 5)))
+	assert.Equal(t, NewClosedRange(3, 4), NewClosedRange(9, 0).Span(NewClosedRange(3, 4)))
+	assert.Equal(t, NewClosedRange(3, 4), NewClosedRange(3, 4).Span(NewClosedRange(9, 0)))
+}
+
+func TestRangeSubtract(t *testing.T) {
+	r := NewClosedRange(0, 10)
+
+	assert.Equal(t, []Range[int]{NewClosedOpenRange(0, 3), NewOpenClosedRange(5, 10)}, r.Subtract(NewClosedRange(3, 5)))
+	assert.Equal(t, []Range[int]{NewClosedRange(0, 3), NewClosedRange(5, 10)}, r.Subtract(NewOpenRange(3, 5)))
+	assert.Equal(t, []Range[int]{NewOpenClosedRange(5, 10)}, r.Subtract(NewClosedRange(-5, 5)))
+	assert.Equal(t, []Range[int]{NewClosedRange(0, 0)}, r.Subtract(NewOpenClosedRange(0, 10)))
+	assert.Equal(t, []Range[int]{r}, r.Subtract(NewClosedRange(20, 30)))
+	assert.Equal(t, 0, len(r.Subtract(NewClosedRange(-1, 11))))
+	assert.Nil(t, NewOpenRange(1, 1).Subtract(r))
+}
+
+func TestIntervalSet(t *testing.T) {
+	var set IntervalSet[int]
+	assert.True(t, set.IsEmpty())
+	assert.False(t, set.Contains(1))
+	_, found := set.Bounds()
+	assert.False(t, found)
+
+	set.Add(NewClosedRange(10, 20))
+	set.Add(NewClosedRange(1, 3))
+	set.Add(NewClosedRange(30, 40))
+	set.Add(NewClosedRange(3, 5))
+	set.Add(NewOpenRange(7, 7))
+	assert.Equal(t, "{[1, 5], [10, 20], [30, 40]}", set.String())
+
+	// touching ranges merge, separated ones do not
+	set.Add(NewOpenClosedRange(20, 25))
+	set.Add(NewOpenRange(40, 45))
+	set.Add(NewOpenRange(50, 55))
+	set.Add(NewClosedOpenRange(45, 50))
+	assert.Equal(t, "{[1, 5], [10, 25], [30, 50), (50, 55)}", set.String())
+
+	// spanning several ranges
+	set.Add(NewClosedRange(4, 45))
+	assert.Equal(t, "{[1, 50), (50, 55)}", set.String())
+	assert.Equal(t, 2, set.Len())
+	assert.Equal(t, 54, set.TotalLength())
+
+	assert.True(t, set.Contains(1))
+	assert.True(t, set.Contains(45))
+	assert.True(t, set.Contains(51))
+	assert.False(t, set.Contains(50))
+	assert.False(t, set.Contains(55))
+	assert.False(t, set.Contains(0))
+
+	bounds, found := set.Bounds()
+	assert.True(t, found)
+	assert.Equal(t, NewClosedOpenRange(1, 55), bounds)
+
+	assert.True(t, set.ContainsRange(NewClosedRange(2, 49)))
+	assert.False(t, set.ContainsRange(NewClosedRange(49, 51)))
+	assert.True(t, set.ContainsRange(NewOpenRange(50, 54)))
+
+	assert.False(t, set.Overlaps(NewClosedRange(55, 60)))
+	assert.False(t, set.Overlaps(NewClosedRange(50, 50)))
+	assert.True(t, set.Overlaps(NewClosedRange(50, 60)))
+	assert.Equal(t, []Range[int]{NewClosedOpenRange(1, 50), NewOpenRange(50, 55)}, set.Query(NewClosedRange(49, 51)))
+	assert.Equal(t, []Range[int]{NewOpenRange(50, 55)}, set.Query(NewClosedRange(50, 51)))
+	assert.Equal(t, 0, len(set.Query(NewClosedRange(100, 200))))
+
+	ranges := set.Ranges()
+	ranges[0] = NewClosedRange(0, 0)
+	assert.Equal(t, NewClosedOpenRange(1, 50), set.Ranges()[0])
+
+	set.Clear()
+	assert.True(t, set.IsEmpty())
+}
+
+func TestIntervalSetRemove(t *testing.T) {
+	set := NewIntervalSet(NewClosedRange(0, 10), NewClosedRange(20, 30), NewClosedRange(40, 50))
+
+	set.Remove(NewClosedRange(5, 25))
+	assert.Equal(t, "{[0, 5), (25, 30], [40, 50]}", set.String())
+
+	set.Remove(NewClosedRange(60, 70))
+	set.Remove(NewOpenRange(30, 40))
+	assert.Equal(t, 3, set.Len())
+
+	set.Remove(NewOpenRange(40, 50))
+	assert.Equal(t, "{[0, 5), (25, 30], [40, 40], [50, 50]}", set.String())
+
+	other := NewIntervalSet(NewClosedRange(0, 100))
+	other.Subtract(set)
+	assert.Equal(t, "{[5, 25], (30, 40), (40, 50), (50, 100]}", other.String())
+
+	other.Union(set)
+	assert.Equal(t, "{[0, 100]}", other.String())
+}
+
+func TestIntervalSetFloats(t *testing.T) {
+	set := NewIntervalSet(NewClosedOpenRange(0.0, 1.5), NewClosedOpenRange(1.5, 3.0))
+	assert.Equal(t, 1, set.Len())
+	assert.True(t, set.Contains(1.5))
+	assert.False(t, set.Contains(3.0))
+	assert.Equal(t, 3.0, set.TotalLength())
+}
+
+func TestIntervalSetRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	randomRange := func() Range[float64] {
+		lower := float64(random.Intn(40))
+		return Range[float64]{
+			Lower:     lower,
+			Upper:     lower + float64(random.Intn(8)),
+			LowerOpen: random.Intn(2) == 0,
+			UpperOpen: random.Intn(2) == 0,
+		}
+	}
+
+	// the numbers checked are the bounds and the points between them
+	contains := func(ranges []Range[float64], value float64) bool {
+		for _, r := range ranges {
+			if r.Contains(value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for round := 0; round < 200; round++ {
+		added := make([]Range[float64], 0)
+		for count := random.Intn(10); count > 0; count-- {
+			added = append(added, randomRange())
+		}
+		removed := make([]Range[float64], 0)
+		for count := random.Intn(6); count > 0; count-- {
+			removed = append(removed, randomRange())
+		}
+
+		built := NewIntervalSet(added...)
+		bySteps := &IntervalSet[float64]{}
+		for _, r := range added {
+			bySteps.Add(r)
+		}
+		assert.Equal(t, built.Ranges(), bySteps.Ranges())
+
+		subtracted := NewIntervalSet(added...).Subtract(NewIntervalSet(removed...))
+		for _, r := range removed {
+			bySteps.Remove(r)
+		}
+		assert.Equal(t, subtracted.Ranges(), bySteps.Ranges())
+
+		joined := NewIntervalSet(added[:len(added)/2]...).Union(NewIntervalSet(added[len(added)/2:]...))
+		assert.Equal(t, built.Ranges(), joined.Ranges())
+
+		for point := -1.0; point <= 50; point += 0.5 {
+			expected := contains(added, point) && !contains(removed, point)
+			assert.Equal(t, expected, subtracted.Contains(point), point)
+			assert.Equal(t, contains(added, point), built.Contains(point), point)
+		}
+
+		// stored ranges are sorted and separated by gaps
+		ranges := subtracted.Ranges()
+		for index := 1; index < len(ranges); index++ {
+			assert.True(t, endsBeforeWithGap(ranges[index-1], ranges[index]))
+		}
+	}
+}
@@ -0,0 +1,325 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"fmt"
+	"sort"
+)
+
+//
+// Return the keys of the map as a slice. The order of keys
+// is not defined. Returns `nil` if the map is `nil`.
+//
+func MapKeys[K comparable, V any](m map[K]V) []K {
+	if m == nil {
+		return nil
+	}
+
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+//
+// Return the keys of the map as a slice sorted in ascending
+// order. Returns `nil` if the map is `nil`.
+//
+func MapKeysSorted[K Ordered, V any](m map[K]V) []K {
+	keys := MapKeys(m)
+	if keys == nil {
+		return nil
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+//
+// Return the values of the map as a slice. The order of values
+// is not defined. Returns `nil` if the map is `nil`.
+//
+func MapValues[K comparable, V any](m map[K]V) []V {
+	if m == nil {
+		return nil
+	}
+
+	values := make([]V, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+//
+// Return the values of the map as a slice sorted in ascending
+// order. Returns `nil` if the map is `nil`.
+//
+func MapValuesSorted[K comparable, V Ordered](m map[K]V) []V {
+	values := MapValues(m)
+	if values == nil {
+		return nil
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i] < values[j]
+	})
+
+	return values
+}
+
+//
+// Return a new map containing only the entries for which the
+// predicate returns `true`. Returns `nil` if the map is `nil`.
+//
+func MapFilter[K comparable, V any](m map[K]V, predicate func(key K, value V) bool) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[K]V)
+	for key, value := range m {
+		if predicate(key, value) {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+//
+// Return a new map with the same keys, where each value is
+// the result of the transformer function. Returns `nil` if the
+// map is `nil`.
+//
+func MapTransformValues[K comparable, V any, R any](m map[K]V, transformer func(key K, value V) R) map[K]R {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[K]R, len(m))
+	for key, value := range m {
+		result[key] = transformer(key, value)
+	}
+
+	return result
+}
+
+//
+// Invert the map so that values become keys and keys become
+// values. If more than one key maps to the same value, the key
+// whose text, as by `fmt.Sprint`, sorts first is retained in the
+// inverted map and all such values are reported in the second
+// map along with every key that mapped to them, in the same
+// order. The second map is `nil` if there were no collisions.
+// Returns `nil` maps if the map is `nil`.
+//
+func MapInvert[K comparable, V comparable](m map[K]V) (map[V]K, map[V][]K) {
+	if m == nil {
+		return nil, nil
+	}
+
+	inverted := make(map[V]K, len(m))
+	var collisions map[V][]K
+	for _, key := range sortKeysByText(MapKeys(m)) {
+		value := m[key]
+		existing, found := inverted[value]
+		if found {
+			if collisions == nil {
+				collisions = make(map[V][]K)
+			}
+
+			if _, reported := collisions[value]; !reported {
+				collisions[value] = []K{existing}
+			}
+			collisions[value] = append(collisions[value], key)
+			continue
+		}
+
+		inverted[value] = key
+	}
+
+	return inverted, collisions
+}
+
+// sort the keys by their text as given by `fmt.Sprint`, for
+// keys that are not `Ordered`
+func sortKeysByText[K comparable](keys []K) []K {
+	texts := make(map[K]string, len(keys))
+	for _, key := range keys {
+		texts[key] = fmt.Sprint(key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return texts[keys[i]] < texts[keys[j]]
+	})
+
+	return keys
+}
+
+//
+// Merge all given maps into a new map. Maps are merged from
+// left to right. If a key is present in more than one map, the
+// resolver is called with the key, the value merged so far and
+// the incoming value, and its result is retained. If resolver is
+// `nil` the incoming value wins. `nil` maps are skipped.
+//
+func MapMerge[K comparable, V any](resolver func(key K, existing V, incoming V) V, maps ...map[K]V) map[K]V {
+	result := make(map[K]V)
+	for _, m := range maps {
+		for key, value := range m {
+			existing, found := result[key]
+			if found && resolver != nil {
+				value = resolver(key, existing, value)
+			}
+
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+//
+// Deep merge the `source` map into the `destination` map and
+// return the result as a new map. Neither of the maps is modified.
+// Nested `map[string]interface{}` values present in both maps are
+// merged recursively, for all other values the value from `source`
+// wins. Nested `map[string]interface{}` and `[]interface{}` values
+// are copied, so that changing the result never changes either
+// of the maps. Returns `nil` if both maps are `nil`.
+//
+func MapDeepMerge(destination map[string]interface{}, source map[string]interface{}) map[string]interface{} {
+	if destination == nil && source == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(destination))
+	for key, value := range destination {
+		result[key] = deepCopyValue(value)
+	}
+
+	for key, value := range source {
+		incoming, incomingIsMap := value.(map[string]interface{})
+		existing, existingIsMap := result[key].(map[string]interface{})
+		if incomingIsMap && existingIsMap {
+			result[key] = MapDeepMerge(existing, incoming)
+			continue
+		}
+
+		result[key] = deepCopyValue(value)
+	}
+
+	return result
+}
+
+// copy nested `map[string]interface{}` and `[]interface{}`
+// values, returning other values as is
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopyValue(item)
+		}
+		return copied
+
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+
+		copied := make([]interface{}, len(v))
+		for index, item := range v {
+			copied[index] = deepCopyValue(item)
+		}
+		return copied
+	}
+
+	return value
+}
+
+//
+// Return a new map that contains only the given keys. Keys not
+// present in the map are ignored. Returns `nil` if the map is `nil`.
+//
+func MapPick[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, found := m[key]; found {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+//
+// Return a new map that contains all but the given keys.
+// Returns `nil` if the map is `nil`.
+//
+func MapOmit[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[K]V, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+
+	for _, key := range keys {
+		delete(result, key)
+	}
+
+	return result
+}
+
+//
+// Check if 2 maps are equal or not. The maps are considered
+// equal if and only if they have the same number of entries and
+// each key maps to an equal value in both maps. If any of the
+// map is `nil` will return a `false`.
+//
+func AreMapsEqual[K comparable, V comparable](map1 map[K]V, map2 map[K]V) bool {
+	if map1 == nil || map2 == nil {
+		return false
+	}
+
+	if len(map1) != len(map2) {
+		return false
+	}
+
+	for key, value := range map1 {
+		other, found := map2[key]
+		if !found || other != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapKeys(t *testing.T) {
+	var foo map[string]int
+	assert.Nil(t, MapKeys(foo))
+	assert.Nil(t, MapKeysSorted(foo))
+
+	foo = map[string]int{"c": 1, "a": 2, "b": 3}
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, MapKeys(foo))
+	assert.Equal(t, []string{"a", "b", "c"}, MapKeysSorted(foo))
+}
+
+func TestMapValues(t *testing.T) {
+	var foo map[string]int
+	assert.Nil(t, MapValues(foo))
+	assert.Nil(t, MapValuesSorted(foo))
+
+	foo = map[string]int{"c": 1, "a": 3, "b": 2}
+	assert.ElementsMatch(t, []int{1, 2, 3}, MapValues(foo))
+	assert.Equal(t, []int{1, 2, 3}, MapValuesSorted(foo))
+}
+
+func TestMapFilter(t *testing.T) {
+	even := func(key string, value int) bool {
+		return value%2 == 0
+	}
+
+	var foo map[string]int
+	assert.Nil(t, MapFilter(foo, even))
+
+	foo = map[string]int{"a": 1, "b": 2, "c": 4}
+	assert.Equal(t, map[string]int{"b": 2, "c": 4}, MapFilter(foo, even))
+}
+
+func TestMapTransformValues(t *testing.T) {
+	upper := func(key string, value string) string {
+		return strings.ToUpper(value)
+	}
+
+	var foo map[string]string
+	assert.Nil(t, MapTransformValues(foo, upper))
+
+	foo = map[string]string{"a": "x", "b": "y"}
+	assert.Equal(t, map[string]string{"a": "X", "b": "Y"}, MapTransformValues(foo, upper))
+}
+
+func TestMapInvert(t *testing.T) {
+	var foo map[string]int
+	inverted, collisions := MapInvert(foo)
+	assert.Nil(t, inverted)
+	assert.Nil(t, collisions)
+
+	foo = map[string]int{"a": 1, "b": 2}
+	inverted, collisions = MapInvert(foo)
+	assert.Equal(t, map[int]string{1: "a", 2: "b"}, inverted)
+	assert.Nil(t, collisions)
+
+	foo = map[string]int{"a": 1, "b": 2, "c": 1, "d": 1}
+	inverted, collisions = MapInvert(foo)
+	assert.Equal(t, 2, len(inverted))
+	assert.Equal(t, "b", inverted[2])
+	assert.Equal(t, "a", inverted[1])
+	assert.Equal(t, 1, len(collisions))
+	assert.Equal(t, []string{"a", "c", "d"}, collisions[1])
+
+	// the first key by its text wins every time
+	for round := 0; round < 10; round++ {
+		byName, collided := MapInvert(map[int]string{3: "x", 1: "x", 2: "x", 4: "y"})
+		assert.Equal(t, map[string]int{"x": 1, "y": 4}, byName)
+		assert.Equal(t, map[string][]int{"x": {1, 2, 3}}, collided)
+	}
+}
+
+func TestMapMerge(t *testing.T) {
+	first := map[string]int{"a": 1, "b": 2}
+	second := map[string]int{"b": 3, "c": 4}
+
+	// last wins
+	assert.Equal(t, map[string]int{"a": 1, "b": 3, "c": 4}, MapMerge(nil, first, nil, second))
+
+	// resolver
+	sum := func(key string, existing int, incoming int) int {
+		return existing + incoming
+	}
+	assert.Equal(t, map[string]int{"a": 1, "b": 5, "c": 4}, MapMerge(sum, first, second))
+
+	// inputs untouched
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, first)
+}
+
+func TestMapDeepMerge(t *testing.T) {
+	assert.Nil(t, MapDeepMerge(nil, nil))
+
+	destination := map[string]interface{}{
+		"name": "server",
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": 5432,
+		},
+	}
+	source := map[string]interface{}{
+		"db": map[string]interface{}{
+			"port": 6432,
+			"user": "admin",
+		},
+		"debug": true,
+	}
+
+	expected := map[string]interface{}{
+		"name": "server",
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": 6432,
+			"user": "admin",
+		},
+		"debug": true,
+	}
+	assert.Equal(t, expected, MapDeepMerge(destination, source))
+
+	// destination untouched
+	assert.Equal(t, 5432, destination["db"].(map[string]interface{})["port"])
+
+	// nested values in only one map are copied
+	destination = map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}}
+	source = map[string]interface{}{"cache": map[string]interface{}{"size": 10}, "hosts": []interface{}{map[string]interface{}{"name": "a"}}}
+	merged := MapDeepMerge(destination, source)
+	merged["db"].(map[string]interface{})["host"] = "remote"
+	merged["cache"].(map[string]interface{})["size"] = 20
+	merged["hosts"].([]interface{})[0].(map[string]interface{})["name"] = "b"
+	assert.Equal(t, "localhost", destination["db"].(map[string]interface{})["host"])
+	assert.Equal(t, 10, source["cache"].(map[string]interface{})["size"])
+	assert.Equal(t, "a", source["hosts"].([]interface{})[0].(map[string]interface{})["name"])
+}
+
+func TestMapPickAndOmit(t *testing.T) {
+	var foo map[string]int
+	assert.Nil(t, MapPick(foo, "a"))
+	assert.Nil(t, MapOmit(foo, "a"))
+
+	foo = map[string]int{"a": 1, "b": 2, "c": 3}
+	assert.Equal(t, map[string]int{"a": 1, "c": 3}, MapPick(foo, "a", "c", "z"))
+	assert.Equal(t, map[string]int{"b": 2}, MapOmit(foo, "a", "c", "z"))
+	assert.Equal(t, 3, len(foo))
+}
+
+func TestAreMapsEqual(t *testing.T) {
+	var foo1 map[string]int
+	foo2 := map[string]int{"a": 1}
+
+	// nil
+	assert.False(t, AreMapsEqual(foo1, foo2))
+	assert.False(t, AreMapsEqual(foo2, foo1))
+
+	// diff length
+	foo1 = map[string]int{"a": 1, "b": 2}
+	assert.False(t, AreMapsEqual(foo1, foo2))
+
+	// same length
+	foo2 = map[string]int{"a": 1, "b": 3}
+	assert.False(t, AreMapsEqual(foo1, foo2))
+
+	foo2 = map[string]int{"a": 1, "c": 2}
+	assert.False(t, AreMapsEqual(foo1, foo2))
+
+	foo2 = map[string]int{"b": 2, "a": 1}
+	assert.True(t, AreMapsEqual(foo1, foo2))
+}
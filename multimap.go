@@ -0,0 +1,496 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+//
+// A map that holds any number of values for each key. Values
+// for a key are kept in the order they were added. A multimap
+// with set semantics ignores a value already present for the
+// key. Keys with no values are removed. The zero value is an
+// empty multimap with list semantics ready to use. The multimap
+// is not safe for concurrent use.
+//
+type MultiMap[K comparable, V comparable] struct {
+	entries  map[K][]V
+	distinct bool
+	size     int
+}
+
+//
+// Create a new empty multimap that allows the same value more
+// than once for a key.
+//
+func NewMultiMap[K comparable, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		entries: make(map[K][]V),
+	}
+}
+
+//
+// Create a new empty multimap that holds each value at most
+// once for a key.
+//
+func NewSetMultiMap[K comparable, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		entries:  make(map[K][]V),
+		distinct: true,
+	}
+}
+
+//
+// Add the value for the key. Returns `false` if the multimap
+// has set semantics and the value is already present.
+//
+func (mm *MultiMap[K, V]) Put(key K, value V) bool {
+	if mm.distinct && SliceContains(mm.entries[key], value) {
+		return false
+	}
+
+	if mm.entries == nil {
+		mm.entries = make(map[K][]V)
+	}
+
+	mm.entries[key] = append(mm.entries[key], value)
+	mm.size++
+	return true
+}
+
+//
+// Add all the values for the key. Returns the number of values
+// that were added.
+//
+func (mm *MultiMap[K, V]) PutAll(key K, values ...V) int {
+	added := 0
+	for _, value := range values {
+		if mm.Put(key, value) {
+			added++
+		}
+	}
+
+	return added
+}
+
+//
+// Return a copy of the values for the key, or `nil` if the key
+// is not present.
+//
+func (mm *MultiMap[K, V]) Get(key K) []V {
+	values, found := mm.entries[key]
+	if !found {
+		return nil
+	}
+
+	result := make([]V, len(values))
+	copy(result, values)
+	return result
+}
+
+//
+// Check if the key has at least one value.
+//
+func (mm *MultiMap[K, V]) Has(key K) bool {
+	_, found := mm.entries[key]
+	return found
+}
+
+//
+// Check if the value is present for the key.
+//
+func (mm *MultiMap[K, V]) HasEntry(key K, value V) bool {
+	return SliceContains(mm.entries[key], value)
+}
+
+//
+// Remove the first occurrence of the value for the key.
+// Returns `false` if the value was not present.
+//
+func (mm *MultiMap[K, V]) Remove(key K, value V) bool {
+	values := mm.entries[key]
+	for index, item := range values {
+		if item != value {
+			continue
+		}
+
+		if len(values) == 1 {
+			delete(mm.entries, key)
+		} else {
+			mm.entries[key] = append(values[:index:index], values[index+1:]...)
+		}
+
+		mm.size--
+		return true
+	}
+
+	return false
+}
+
+//
+// Remove the key with all its values, and return the values
+// that were removed.
+//
+func (mm *MultiMap[K, V]) RemoveAll(key K) []V {
+	values, found := mm.entries[key]
+	if !found {
+		return nil
+	}
+
+	delete(mm.entries, key)
+	mm.size -= len(values)
+	return values
+}
+
+//
+// Return the number of values for the key.
+//
+func (mm *MultiMap[K, V]) Count(key K) int {
+	return len(mm.entries[key])
+}
+
+//
+// Return the total number of values across all keys.
+//
+func (mm *MultiMap[K, V]) Len() int {
+	return mm.size
+}
+
+//
+// Return the number of keys that have at least one value.
+//
+func (mm *MultiMap[K, V]) KeyCount() int {
+	return len(mm.entries)
+}
+
+//
+// Return the keys in no particular order.
+//
+func (mm *MultiMap[K, V]) Keys() []K {
+	return MapKeys(mm.entries)
+}
+
+//
+// Return every key and value pair, with keys in no particular
+// order and values of a key in the order they were added.
+//
+func (mm *MultiMap[K, V]) Entries() []MapEntry[K, V] {
+	entries := make([]MapEntry[K, V], 0, mm.size)
+	for key, values := range mm.entries {
+		for _, value := range values {
+			entries = append(entries, MapEntry[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return entries
+}
+
+//
+// Call the consumer for each key and value pair, stopping when
+// it returns `false`. The multimap must not be modified while
+// iterating.
+//
+func (mm *MultiMap[K, V]) ForEach(consumer func(key K, value V) bool) {
+	for key, values := range mm.entries {
+		for _, value := range values {
+			if !consumer(key, value) {
+				return
+			}
+		}
+	}
+}
+
+//
+// Return a copy of the entries as a plain Go map.
+//
+func (mm *MultiMap[K, V]) ToMap() map[K][]V {
+	result := make(map[K][]V, len(mm.entries))
+	for key, values := range mm.entries {
+		result[key] = append([]V(nil), values...)
+	}
+
+	return result
+}
+
+//
+// Remove all keys and values.
+//
+func (mm *MultiMap[K, V]) Clear() {
+	mm.entries = make(map[K][]V)
+	mm.size = 0
+}
+
+//
+// Encode the multimap as a JSON object of arrays.
+//
+func (mm *MultiMap[K, V]) MarshalJSON() ([]byte, error) {
+	if mm.entries == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(mm.entries)
+}
+
+//
+// Decode a JSON object of arrays into the multimap, replacing
+// all existing entries. A multimap with set semantics drops
+// repeated values for a key.
+//
+func (mm *MultiMap[K, V]) UnmarshalJSON(data []byte) error {
+	var decoded map[K][]V
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	mm.Clear()
+	for key, values := range decoded {
+		mm.PutAll(key, values...)
+	}
+
+	return nil
+}
+
+//
+// Returned by `BiMap.Put` when the value is already mapped to
+// a different key.
+//
+var ErrDuplicateValue = errors.New("Value already mapped to another key")
+
+//
+// A map that keeps keys and values unique in both directions,
+// so that a key can be looked up by its value. The zero value is
+// an empty map ready to use. The map is not safe for concurrent
+// use.
+//
+type BiMap[K comparable, V comparable] struct {
+	forward  map[K]V
+	backward map[V]K
+}
+
+//
+// Create a new empty bidirectional map.
+//
+func NewBiMap[K comparable, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{
+		forward:  make(map[K]V),
+		backward: make(map[V]K),
+	}
+}
+
+// create the maps on first use
+func (bm *BiMap[K, V]) init() {
+	if bm.forward == nil {
+		bm.forward = make(map[K]V)
+		bm.backward = make(map[V]K)
+	}
+}
+
+//
+// Set the value for the key, replacing any value the key had.
+// Returns `ErrDuplicateValue` and leaves the map unchanged if
+// the value is already mapped to a different key.
+//
+func (bm *BiMap[K, V]) Put(key K, value V) error {
+	if existing, found := bm.backward[value]; found && existing != key {
+		return ErrDuplicateValue
+	}
+
+	bm.ForcePut(key, value)
+	return nil
+}
+
+//
+// Set the value for the key, removing any other key that the
+// value was mapped to.
+//
+func (bm *BiMap[K, V]) ForcePut(key K, value V) {
+	bm.init()
+
+	if existing, found := bm.forward[key]; found {
+		delete(bm.backward, existing)
+	}
+	if existing, found := bm.backward[value]; found {
+		delete(bm.forward, existing)
+	}
+
+	bm.forward[key] = value
+	bm.backward[value] = key
+}
+
+//
+// Return the value for the key, and `false` if the key is
+// not present.
+//
+func (bm *BiMap[K, V]) Get(key K) (V, bool) {
+	value, found := bm.forward[key]
+	return value, found
+}
+
+//
+// Return the key for the value, and `false` if the value is
+// not present.
+//
+func (bm *BiMap[K, V]) GetKey(value V) (K, bool) {
+	key, found := bm.backward[value]
+	return key, found
+}
+
+//
+// Check if the key is present.
+//
+func (bm *BiMap[K, V]) HasKey(key K) bool {
+	_, found := bm.forward[key]
+	return found
+}
+
+//
+// Check if the value is present.
+//
+func (bm *BiMap[K, V]) HasValue(value V) bool {
+	_, found := bm.backward[value]
+	return found
+}
+
+//
+// Remove the key and its value. Returns `false` if the key was
+// not present.
+//
+func (bm *BiMap[K, V]) Remove(key K) bool {
+	value, found := bm.forward[key]
+	if !found {
+		return false
+	}
+
+	delete(bm.forward, key)
+	delete(bm.backward, value)
+	return true
+}
+
+//
+// Remove the value and its key. Returns `false` if the value
+// was not present.
+//
+func (bm *BiMap[K, V]) RemoveValue(value V) bool {
+	key, found := bm.backward[value]
+	if !found {
+		return false
+	}
+
+	delete(bm.backward, value)
+	delete(bm.forward, key)
+	return true
+}
+
+//
+// Return the number of entries in the map.
+//
+func (bm *BiMap[K, V]) Len() int {
+	return len(bm.forward)
+}
+
+//
+// Return the keys in no particular order.
+//
+func (bm *BiMap[K, V]) Keys() []K {
+	return MapKeys(bm.forward)
+}
+
+//
+// Return the values in no particular order.
+//
+func (bm *BiMap[K, V]) Values() []V {
+	return MapKeys(bm.backward)
+}
+
+//
+// Return a view of the map with keys and values swapped. The
+// view shares its entries with this map, so changes to either
+// are visible in both.
+//
+func (bm *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	bm.init()
+
+	return &BiMap[V, K]{
+		forward:  bm.backward,
+		backward: bm.forward,
+	}
+}
+
+//
+// Return a copy of the entries as a plain Go map.
+//
+func (bm *BiMap[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, len(bm.forward))
+	for key, value := range bm.forward {
+		result[key] = value
+	}
+
+	return result
+}
+
+//
+// Remove all entries from the map. Inverse views see the map
+// as empty too.
+//
+func (bm *BiMap[K, V]) Clear() {
+	for key := range bm.forward {
+		delete(bm.forward, key)
+	}
+	for value := range bm.backward {
+		delete(bm.backward, value)
+	}
+}
+
+//
+// Encode the map as a JSON object from keys to values.
+//
+func (bm *BiMap[K, V]) MarshalJSON() ([]byte, error) {
+	if bm.forward == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(bm.forward)
+}
+
+//
+// Decode a JSON object into the map, replacing all existing
+// entries. Returns an error wrapping `ErrDuplicateValue` that
+// names the second of the two keys, in sorted order, if two
+// keys have the same value. The map is left unchanged if an
+// error is returned.
+//
+func (bm *BiMap[K, V]) UnmarshalJSON(data []byte) error {
+	var decoded map[K]V
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	decodedMap := NewBiMap[K, V]()
+	for _, key := range sortKeysByText(MapKeys(decoded)) {
+		if err := decodedMap.Put(key, decoded[key]); err != nil {
+			return fmt.Errorf("%w: %v", err, key)
+		}
+	}
+
+	// fill the existing maps, which inverse views share
+	bm.Clear()
+	bm.init()
+	for key, value := range decodedMap.forward {
+		bm.forward[key] = value
+		bm.backward[value] = key
+	}
+
+	return nil
+}
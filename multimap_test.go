@@ -0,0 +1,201 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiMap(t *testing.T) {
+	var mm MultiMap[string, int]
+	assert.Nil(t, mm.Get("a"))
+	assert.Equal(t, 0, mm.Len())
+
+	assert.True(t, mm.Put("a", 1))
+	assert.True(t, mm.Put("a", 1))
+	assert.Equal(t, 2, mm.PutAll("a", 2, 3))
+	mm.Put("b", 10)
+
+	assert.Equal(t, []int{1, 1, 2, 3}, mm.Get("a"))
+	assert.Equal(t, 4, mm.Count("a"))
+	assert.Equal(t, 0, mm.Count("z"))
+	assert.Equal(t, 5, mm.Len())
+	assert.Equal(t, 2, mm.KeyCount())
+	assert.ElementsMatch(t, []string{"a", "b"}, mm.Keys())
+	assert.True(t, mm.Has("b"))
+	assert.True(t, mm.HasEntry("a", 2))
+	assert.False(t, mm.HasEntry("b", 2))
+
+	// returned values are a copy
+	values := mm.Get("a")
+	values[0] = 100
+	assert.Equal(t, 1, mm.Get("a")[0])
+
+	assert.True(t, mm.Remove("a", 1))
+	assert.Equal(t, []int{1, 2, 3}, mm.Get("a"))
+	assert.False(t, mm.Remove("a", 9))
+	assert.True(t, mm.Remove("b", 10))
+	assert.False(t, mm.Has("b"))
+	assert.Equal(t, 3, mm.Len())
+
+	mm.Put("c", 7)
+	assert.ElementsMatch(t, []MapEntry[string, int]{{"a", 1}, {"a", 2}, {"a", 3}, {"c", 7}}, mm.Entries())
+	assert.Equal(t, map[string][]int{"a": {1, 2, 3}, "c": {7}}, mm.ToMap())
+
+	visited := 0
+	mm.ForEach(func(key string, value int) bool {
+		visited++
+		return visited < 2
+	})
+	assert.Equal(t, 2, visited)
+
+	assert.Equal(t, []int{1, 2, 3}, mm.RemoveAll("a"))
+	assert.Nil(t, mm.RemoveAll("a"))
+	assert.Equal(t, 1, mm.Len())
+
+	mm.Clear()
+	assert.Equal(t, 0, mm.Len())
+	assert.Equal(t, 0, mm.KeyCount())
+}
+
+func TestSetMultiMap(t *testing.T) {
+	mm := NewSetMultiMap[string, string]()
+	assert.True(t, mm.Put("tags", "go"))
+	assert.False(t, mm.Put("tags", "go"))
+	assert.Equal(t, 1, mm.PutAll("tags", "go", "generics"))
+	assert.Equal(t, []string{"go", "generics"}, mm.Get("tags"))
+	assert.Equal(t, 2, mm.Len())
+}
+
+func TestMultiMapJSON(t *testing.T) {
+	mm := NewMultiMap[string, int]()
+	mm.PutAll("b", 2, 2)
+	mm.Put("a", 1)
+
+	data, err := json.Marshal(mm)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":[1],"b":[2,2]}`, string(data))
+
+	var empty MultiMap[string, int]
+	data, err = json.Marshal(&empty)
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+
+	decoded := NewSetMultiMap[int, int]()
+	decoded.Put(9, 9)
+	assert.NoError(t, json.Unmarshal([]byte(`{"1":[5,5,6],"2":[7]}`), decoded))
+	assert.Equal(t, map[int][]int{1: {5, 6}, 2: {7}}, decoded.ToMap())
+	assert.Equal(t, 3, decoded.Len())
+
+	assert.Error(t, json.Unmarshal([]byte(`[1]`), decoded))
+}
+
+func TestBiMap(t *testing.T) {
+	var bm BiMap[string, int]
+	_, found := bm.Get("a")
+	assert.False(t, found)
+
+	assert.NoError(t, bm.Put("one", 1))
+	assert.NoError(t, bm.Put("two", 2))
+	assert.NoError(t, bm.Put("two", 2))
+	assert.Equal(t, 2, bm.Len())
+
+	value, found := bm.Get("one")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	key, found := bm.GetKey(2)
+	assert.True(t, found)
+	assert.Equal(t, "two", key)
+
+	// values are unique
+	assert.ErrorIs(t, bm.Put("uno", 1), ErrDuplicateValue)
+	assert.False(t, bm.HasKey("uno"))
+
+	// replacing the value of a key frees the old value
+	assert.NoError(t, bm.Put("one", 11))
+	assert.False(t, bm.HasValue(1))
+	assert.NoError(t, bm.Put("uno", 1))
+
+	// force put removes the conflicting key
+	bm.ForcePut("eins", 1)
+	assert.False(t, bm.HasKey("uno"))
+	assert.Equal(t, map[string]int{"one": 11, "two": 2, "eins": 1}, bm.ToMap())
+	assert.ElementsMatch(t, []string{"one", "two", "eins"}, bm.Keys())
+	assert.ElementsMatch(t, []int{11, 2, 1}, bm.Values())
+
+	assert.True(t, bm.Remove("two"))
+	assert.False(t, bm.Remove("two"))
+	assert.False(t, bm.HasValue(2))
+	assert.True(t, bm.RemoveValue(1))
+	assert.False(t, bm.RemoveValue(1))
+	assert.False(t, bm.HasKey("eins"))
+	assert.Equal(t, 1, bm.Len())
+}
+
+func TestBiMapInverse(t *testing.T) {
+	var bm BiMap[string, int]
+	inverse := bm.Inverse()
+
+	bm.Put("a", 1)
+	key, found := inverse.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, "a", key)
+
+	inverse.Put(2, "b")
+	value, found := bm.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	assert.ErrorIs(t, inverse.Put(3, "a"), ErrDuplicateValue)
+
+	inverse.Clear()
+	assert.Equal(t, 0, bm.Len())
+}
+
+func TestBiMapJSON(t *testing.T) {
+	bm := NewBiMap[string, int]()
+	bm.Put("b", 2)
+	bm.Put("a", 1)
+
+	data, err := json.Marshal(bm)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":2}`, string(data))
+
+	var empty BiMap[string, int]
+	data, err = json.Marshal(&empty)
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+
+	decoded := NewBiMap[string, int]()
+	assert.NoError(t, json.Unmarshal([]byte(`{"x":1,"y":2}`), decoded))
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, decoded.ToMap())
+
+	key, _ := decoded.GetKey(2)
+	assert.Equal(t, "y", key)
+
+	// a failed decode leaves the map unchanged
+	inverse := decoded.Inverse()
+	for round := 0; round < 10; round++ {
+		err = json.Unmarshal([]byte(`{"z":3,"x":1,"y":1,"w":1}`), decoded)
+		assert.ErrorIs(t, err, ErrDuplicateValue)
+		assert.Equal(t, "Value already mapped to another key: x", err.Error())
+		assert.Equal(t, map[string]int{"x": 1, "y": 2}, decoded.ToMap())
+	}
+
+	// a successful one is seen by inverse views
+	assert.NoError(t, json.Unmarshal([]byte(`{"z":3}`), decoded))
+	assert.Equal(t, map[int]string{3: "z"}, inverse.ToMap())
+}
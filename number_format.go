@@ -0,0 +1,313 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//
+// Controls which notations are accepted when numbers are
+// parsed from strings by `ParseNumber` and, when set in the
+// `ConvertOptions`, by the `ConvertTo*` functions. The zero
+// value accepts plain decimal numbers only, like `-42` and
+// `3.14`.
+//
+type NumberFormat struct {
+	// remove leading and trailing whitespace
+	TrimSpace bool
+
+	// accept the prefixes `0x`, `0o` and `0b` for hexadecimal,
+	// octal and binary integers
+	BasePrefixes bool
+
+	// accept underscores between digits, like `1_000`
+	Underscores bool
+
+	// the character grouping thousands in the integer part,
+	// like `,` in `1,000,000`; zero to not accept grouping
+	ThousandsSeparator rune
+
+	// the character separating the fractional part; zero for `.`
+	DecimalSeparator rune
+
+	// accept scientific notation, like `1.5e3`
+	Exponent bool
+
+	// accept a trailing `%`, dividing the number by 100
+	Percent bool
+}
+
+//
+// Return a format accepting every notation: surrounding
+// whitespace, base prefixes, underscores, `,` as the thousands
+// separator, scientific notation and percentages.
+//
+func LenientNumberFormat() *NumberFormat {
+	return &NumberFormat{
+		TrimSpace:          true,
+		BasePrefixes:       true,
+		Underscores:        true,
+		ThousandsSeparator: ',',
+		Exponent:           true,
+		Percent:            true,
+	}
+}
+
+// decimal number after separators have been normalized
+var decimalNumberPattern = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// exponents beyond this are parsed as floats instead of exactly
+const maxExactExponent = 400
+
+//
+// Parse the text as a number using the format. A `nil` format
+// accepts plain decimal numbers only. Returns an `int64` for
+// integers, a `uint64` for integers too large for `int64`, and
+// a `float64` otherwise. Values written with a fraction or an
+// exponent that are whole numbers, like `12.0` or `1e3`, are
+// returned as integers. Integers beyond 64 bits are returned as
+// the nearest `float64`. Returns a `*strconv.NumError` if the
+// text is not a number in the format.
+//
+func ParseNumber(text string, format *NumberFormat) (interface{}, error) {
+	if format == nil {
+		format = &NumberFormat{}
+	}
+
+	syntaxError := &strconv.NumError{Func: "ParseNumber", Num: text, Err: strconv.ErrSyntax}
+
+	body := text
+	if format.TrimSpace {
+		body = strings.TrimSpace(body)
+	}
+
+	percent := false
+	if format.Percent && strings.HasSuffix(body, "%") {
+		percent = true
+		body = strings.TrimSpace(strings.TrimSuffix(body, "%"))
+	}
+
+	negative := false
+	if strings.HasPrefix(body, "-") || strings.HasPrefix(body, "+") {
+		negative = body[0] == '-'
+		body = body[1:]
+	}
+
+	if format.Underscores && strings.Contains(body, "_") {
+		var ok bool
+		if body, ok = removeUnderscores(body, format.BasePrefixes && !percent); !ok {
+			return nil, syntaxError
+		}
+	}
+
+	if format.BasePrefixes && !percent {
+		if base, digits, ok := splitBasePrefix(body); ok {
+			if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+				return nil, syntaxError
+			}
+
+			magnitude, ok := new(big.Int).SetString(digits, base)
+			if !ok {
+				return nil, syntaxError
+			}
+
+			return signedNumber(magnitude, negative), nil
+		}
+	}
+
+	body, ok := normalizeDecimal(body, format)
+	if !ok || !decimalNumberPattern.MatchString(body) {
+		return nil, syntaxError
+	}
+
+	mantissa, exponent := body, 0
+	if index := strings.IndexAny(body, "eE"); index >= 0 {
+		if !format.Exponent {
+			return nil, syntaxError
+		}
+
+		mantissa = body[:index]
+		parsed, err := strconv.Atoi(body[index+1:])
+		if err != nil || parsed > maxExactExponent || parsed < -maxExactExponent {
+			// too far out to be exact, let the float parser round it
+			value, _ := strconv.ParseFloat(body, 64)
+			if percent {
+				value /= 100
+			}
+			if negative {
+				value = -value
+			}
+			return value, nil
+		}
+		exponent = parsed
+	}
+
+	rational, ok := new(big.Rat).SetString(mantissa)
+	if !ok {
+		return nil, syntaxError
+	}
+
+	if percent {
+		exponent -= 2
+	}
+
+	if exponent != 0 {
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt(exponent))), nil))
+		if exponent > 0 {
+			rational.Mul(rational, scale)
+		} else {
+			rational.Quo(rational, scale)
+		}
+	}
+
+	if rational.IsInt() {
+		return signedNumber(rational.Num(), negative), nil
+	}
+
+	value, _ := rational.Float64()
+	if negative {
+		value = -value
+	}
+
+	return value, nil
+}
+
+// return the absolute value of the integer
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+// return the magnitude with its sign as an `int64` or `uint64`
+// if it fits, or the nearest `float64` otherwise
+func signedNumber(magnitude *big.Int, negative bool) interface{} {
+	value := new(big.Int).Set(magnitude)
+	if negative {
+		value.Neg(value)
+	}
+
+	if value.IsInt64() {
+		return value.Int64()
+	}
+
+	if value.IsUint64() {
+		return value.Uint64()
+	}
+
+	converted, _ := new(big.Float).SetInt(value).Float64()
+	return converted
+}
+
+// remove underscores that sit between two digits, failing if
+// any underscore does not. The digits are those of the base given
+// by a `0x`, `0o` or `0b` prefix if `prefixes` is set, or decimal
+// digits otherwise, so that `1_.5`, `0_x1F` and `1e_5` fail.
+func removeUnderscores(text string, prefixes bool) (string, bool) {
+	base := 10
+	if prefixes {
+		if prefixed, _, ok := splitBasePrefix(text); ok {
+			base = prefixed
+		}
+	}
+
+	for index := 0; index < len(text); index++ {
+		if text[index] != '_' {
+			continue
+		}
+
+		if index == 0 || index == len(text)-1 || !isDigitInBase(text[index-1], base) || !isDigitInBase(text[index+1], base) {
+			return "", false
+		}
+	}
+
+	return strings.ReplaceAll(text, "_", ""), true
+}
+
+// check if the character is a digit in the base
+func isDigitInBase(char byte, base int) bool {
+	var digit int
+	switch {
+	case char >= '0' && char <= '9':
+		digit = int(char - '0')
+	case char >= 'a' && char <= 'z':
+		digit = int(char-'a') + 10
+	case char >= 'A' && char <= 'Z':
+		digit = int(char-'A') + 10
+	default:
+		return false
+	}
+
+	return digit < base
+}
+
+// split a `0x`, `0o` or `0b` prefix from the digits
+func splitBasePrefix(text string) (int, string, bool) {
+	if len(text) < 3 || text[0] != '0' {
+		return 0, "", false
+	}
+
+	switch text[1] {
+	case 'x', 'X':
+		return 16, text[2:], true
+	case 'o', 'O':
+		return 8, text[2:], true
+	case 'b', 'B':
+		return 2, text[2:], true
+	}
+
+	return 0, "", false
+}
+
+// remove thousands separators and replace the decimal separator
+// with `.`, checking that the thousands are grouped correctly
+func normalizeDecimal(text string, format *NumberFormat) (string, bool) {
+	decimal := format.DecimalSeparator
+	if decimal == 0 {
+		decimal = '.'
+	}
+
+	integer, rest := text, ""
+	if index := strings.IndexRune(text, decimal); index >= 0 {
+		integer, rest = text[:index], "."+text[index+len(string(decimal)):]
+	} else if index := strings.IndexAny(text, "eE"); index >= 0 {
+		integer, rest = text[:index], text[index:]
+	}
+
+	separator := format.ThousandsSeparator
+	if separator != 0 && separator != decimal && strings.ContainsRune(integer, separator) {
+		groups := strings.Split(integer, string(separator))
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return "", false
+		}
+
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return "", false
+			}
+		}
+
+		integer = strings.Join(groups, "")
+	}
+
+	if decimal != '.' && strings.Contains(integer, ".") {
+		return "", false
+	}
+
+	return integer + rest, true
+}
@@ -0,0 +1,111 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNumberPlain(t *testing.T) {
+	value, err := ParseNumber("-42", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-42), value)
+
+	value, err = ParseNumber("3.25", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.25, value)
+
+	value, err = ParseNumber("12.0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), value)
+
+	value, err = ParseNumber("18446744073709551615", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), value)
+
+	value, err = ParseNumber("100000000000000000000", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1e20, value)
+
+	// notations not enabled
+	for _, text := range []string{" 42", "0x1F", "1_000", "1,000", "1e3", "50%", "", "-", "1.2.3", "abc", "1/2"} {
+		_, err = ParseNumber(text, nil)
+		assert.ErrorIs(t, err, strconv.ErrSyntax, text)
+	}
+}
+
+func TestParseNumberLenient(t *testing.T) {
+	format := LenientNumberFormat()
+
+	tests := []struct {
+		text     string
+		expected interface{}
+	}{
+		{" 42\n", int64(42)},
+		{"+7", int64(7)},
+		{"0x1F", int64(31)},
+		{"-0X10", int64(-16)},
+		{"0o17", int64(15)},
+		{"0b101", int64(5)},
+		{"0xFFFFFFFFFFFFFFFF", uint64(math.MaxUint64)},
+		{"1_000_000", int64(1000000)},
+		{"0xFF_FF", int64(65535)},
+		{"0b1010_1010", int64(170)},
+		{"1_000.2_5", 1000.25},
+		{"1,234,567", int64(1234567)},
+		{"1,234.5", 1234.5},
+		{"1e3", int64(1000)},
+		{"1.5E3", int64(1500)},
+		{"-2.5e-1", -0.25},
+		{"1e400", math.Inf(1)},
+		{"50%", 0.5},
+		{"250 %", 2.5},
+		{"200%", int64(2)},
+		{"1e1000", math.Inf(1)},
+		{".5", 0.5},
+	}
+
+	for _, test := range tests {
+		value, err := ParseNumber(test.text, format)
+		assert.NoError(t, err, test.text)
+		assert.Equal(t, test.expected, value, test.text)
+	}
+
+	for _, text := range []string{"1,00", "1234,567", ",123", "1__0", "_1", "1_", "1_.5", "0_x1F", "0x_1F", "1e_5", "1_e5", "0b1_2", "0x", "0xZZ", "0x-5", "0x1F%", "1e", "e5", "%"} {
+		_, err := ParseNumber(text, format)
+		assert.ErrorIs(t, err, strconv.ErrSyntax, text)
+	}
+}
+
+func TestParseNumberLocale(t *testing.T) {
+	format := &NumberFormat{ThousandsSeparator: '.', DecimalSeparator: ','}
+
+	value, err := ParseNumber("1.234.567,25", format)
+	assert.NoError(t, err)
+	assert.Equal(t, 1234567.25, value)
+
+	value, err = ParseNumber("12,0", format)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), value)
+
+	_, err = ParseNumber("12.5", format)
+	assert.Error(t, err)
+
+	// spaces as thousands separator
+	value, err = ParseNumber("1 000 000", &NumberFormat{ThousandsSeparator: ' '})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000000), value)
+}
@@ -0,0 +1,463 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// a single entry in the ordered map, linked in insertion order
+type orderedMapNode[K comparable, V any] struct {
+	key   K
+	value V
+	prev  *orderedMapNode[K, V]
+	next  *orderedMapNode[K, V]
+}
+
+//
+// A map that remembers the order in which keys were inserted.
+// Get, set and delete run in constant time, and iteration and
+// JSON encoding follow the insertion order. Setting an existing
+// key updates its value without changing its position. The zero
+// value is an empty map ready to use. The map is not safe for
+// concurrent use.
+//
+type OrderedMap[K comparable, V any] struct {
+	nodes map[K]*orderedMapNode[K, V]
+	head  *orderedMapNode[K, V]
+	tail  *orderedMapNode[K, V]
+}
+
+//
+// Create a new empty ordered map.
+//
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		nodes: make(map[K]*orderedMapNode[K, V]),
+	}
+}
+
+//
+// Create a new ordered map from the map with keys inserted in
+// ascending order, so that the result is deterministic.
+//
+func NewOrderedMapFromMap[K Ordered, V any](m map[K]V) *OrderedMap[K, V] {
+	om := NewOrderedMap[K, V]()
+	for _, key := range MapKeysSorted(m) {
+		om.Set(key, m[key])
+	}
+
+	return om
+}
+
+// marks the type for `IsOrderedMap`
+func (om *OrderedMap[K, V]) orderedMap() {}
+
+//
+// Check if value is an `*OrderedMap` of any key and value
+// type. Returns `false` if value is `nil`.
+//
+func IsOrderedMap(value interface{}) bool {
+	_, ok := value.(interface{ orderedMap() })
+	return ok && !reflect.ValueOf(value).IsNil()
+}
+
+//
+// Return the number of entries in the map.
+//
+func (om *OrderedMap[K, V]) Len() int {
+	return len(om.nodes)
+}
+
+//
+// Return the value for the key, and `false` if the key is
+// not present.
+//
+func (om *OrderedMap[K, V]) Get(key K) (V, bool) {
+	node, found := om.nodes[key]
+	if !found {
+		var zero V
+		return zero, false
+	}
+
+	return node.value, true
+}
+
+//
+// Check if the key is present in the map.
+//
+func (om *OrderedMap[K, V]) Has(key K) bool {
+	_, found := om.nodes[key]
+	return found
+}
+
+//
+// Set the value for the key. A new key is added at the end,
+// an existing key retains its position. Returns `true` if the
+// key was newly added.
+//
+func (om *OrderedMap[K, V]) Set(key K, value V) bool {
+	if node, found := om.nodes[key]; found {
+		node.value = value
+		return false
+	}
+
+	if om.nodes == nil {
+		om.nodes = make(map[K]*orderedMapNode[K, V])
+	}
+
+	node := &orderedMapNode[K, V]{key: key, value: value}
+	om.nodes[key] = node
+	om.linkBack(node)
+	return true
+}
+
+//
+// Remove the key from the map. Returns `false` if the key
+// was not present.
+//
+func (om *OrderedMap[K, V]) Delete(key K) bool {
+	node, found := om.nodes[key]
+	if !found {
+		return false
+	}
+
+	om.unlink(node)
+	delete(om.nodes, key)
+	return true
+}
+
+//
+// Move the key to the front of the order. Returns `false` if
+// the key is not present.
+//
+func (om *OrderedMap[K, V]) MoveToFront(key K) bool {
+	node, found := om.nodes[key]
+	if !found {
+		return false
+	}
+
+	om.unlink(node)
+	node.next = om.head
+	if om.head != nil {
+		om.head.prev = node
+	}
+	om.head = node
+	if om.tail == nil {
+		om.tail = node
+	}
+
+	return true
+}
+
+//
+// Move the key to the back of the order. Returns `false` if
+// the key is not present.
+//
+func (om *OrderedMap[K, V]) MoveToBack(key K) bool {
+	node, found := om.nodes[key]
+	if !found {
+		return false
+	}
+
+	om.unlink(node)
+	om.linkBack(node)
+	return true
+}
+
+//
+// Return the first entry in the order, and `false` if the
+// map is empty.
+//
+func (om *OrderedMap[K, V]) Front() (MapEntry[K, V], bool) {
+	if om.head == nil {
+		return MapEntry[K, V]{}, false
+	}
+
+	return MapEntry[K, V]{Key: om.head.key, Value: om.head.value}, true
+}
+
+//
+// Return the last entry in the order, and `false` if the
+// map is empty.
+//
+func (om *OrderedMap[K, V]) Back() (MapEntry[K, V], bool) {
+	if om.tail == nil {
+		return MapEntry[K, V]{}, false
+	}
+
+	return MapEntry[K, V]{Key: om.tail.key, Value: om.tail.value}, true
+}
+
+//
+// Return the keys in order.
+//
+func (om *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(om.nodes))
+	for node := om.head; node != nil; node = node.next {
+		keys = append(keys, node.key)
+	}
+
+	return keys
+}
+
+//
+// Return the values in the order of their keys.
+//
+func (om *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, len(om.nodes))
+	for node := om.head; node != nil; node = node.next {
+		values = append(values, node.value)
+	}
+
+	return values
+}
+
+//
+// Return the entries in order.
+//
+func (om *OrderedMap[K, V]) Entries() []MapEntry[K, V] {
+	entries := make([]MapEntry[K, V], 0, len(om.nodes))
+	for node := om.head; node != nil; node = node.next {
+		entries = append(entries, MapEntry[K, V]{Key: node.key, Value: node.value})
+	}
+
+	return entries
+}
+
+//
+// Call the consumer for each entry in order, stopping when
+// it returns `false`. The map must not be modified while
+// iterating.
+//
+func (om *OrderedMap[K, V]) ForEach(consumer func(key K, value V) bool) {
+	for node := om.head; node != nil; node = node.next {
+		if !consumer(node.key, node.value) {
+			return
+		}
+	}
+}
+
+//
+// Return an iterator over the entries in order. The map must
+// not be modified while iterating.
+//
+func (om *OrderedMap[K, V]) Iterator() Iterator[MapEntry[K, V]] {
+	node := om.head
+	return IteratorFunc[MapEntry[K, V]](func() (MapEntry[K, V], bool) {
+		if node == nil {
+			return MapEntry[K, V]{}, false
+		}
+
+		entry := MapEntry[K, V]{Key: node.key, Value: node.value}
+		node = node.next
+		return entry, true
+	})
+}
+
+//
+// Return a plain Go map with the same entries, for use with
+// the map helpers. The order is lost.
+//
+func (om *OrderedMap[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, len(om.nodes))
+	for key, node := range om.nodes {
+		result[key] = node.value
+	}
+
+	return result
+}
+
+//
+// Remove all entries from the map.
+//
+func (om *OrderedMap[K, V]) Clear() {
+	om.nodes = make(map[K]*orderedMapNode[K, V])
+	om.head = nil
+	om.tail = nil
+}
+
+//
+// Encode the map as a JSON object with keys in order. Keys
+// must be strings, integers or implement `encoding.TextMarshaler`.
+//
+func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	for node := om.head; node != nil; node = node.next {
+		if node != om.head {
+			buffer.WriteByte(',')
+		}
+
+		key, err := orderedMapKeyToString(node.key)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedValue, err := json.Marshal(node.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		buffer.Write(encodedValue)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+//
+// Decode a JSON object into the map, replacing all existing
+// entries and retaining the order of keys in the input. A JSON
+// `null` leaves the map empty.
+//
+func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	om.Clear()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return errors.New("OrderedMap can only be decoded from a JSON object")
+	}
+
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := orderedMapKeyFromString[K](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		// a repeated key keeps its first position and the last value
+		om.Set(key, value)
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// add the node at the end of the list
+func (om *OrderedMap[K, V]) linkBack(node *orderedMapNode[K, V]) {
+	node.prev = om.tail
+	node.next = nil
+	if om.tail != nil {
+		om.tail.next = node
+	}
+	om.tail = node
+	if om.head == nil {
+		om.head = node
+	}
+}
+
+// remove the node from the list
+func (om *OrderedMap[K, V]) unlink(node *orderedMapNode[K, V]) {
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		om.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		om.tail = node.prev
+	}
+
+	node.prev = nil
+	node.next = nil
+}
+
+// convert a map key to a JSON object key
+func orderedMapKeyToString(key interface{}) (string, error) {
+	if marshaler, ok := key.(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	reflected := reflect.ValueOf(key)
+	switch reflected.Kind() {
+	case reflect.String:
+		return reflected.String(), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(reflected.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(reflected.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("Unsupported key type for JSON: %T", key)
+}
+
+// convert a JSON object key to a map key
+func orderedMapKeyFromString[K comparable](text string) (K, error) {
+	var key K
+	if unmarshaler, ok := interface{}(&key).(encoding.TextUnmarshaler); ok {
+		err := unmarshaler.UnmarshalText([]byte(text))
+		return key, err
+	}
+
+	reflected := reflect.ValueOf(&key).Elem()
+	switch reflected.Kind() {
+	case reflect.String:
+		reflected.SetString(text)
+		return key, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(text, 10, reflected.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		reflected.SetInt(number)
+		return key, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		number, err := strconv.ParseUint(text, 10, reflected.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		reflected.SetUint(number)
+		return key, nil
+	}
+
+	return key, fmt.Errorf("Unsupported key type for JSON: %T", key)
+}
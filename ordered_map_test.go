@@ -0,0 +1,146 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var om OrderedMap[string, int]
+	assert.Equal(t, 0, om.Len())
+	_, found := om.Get("a")
+	assert.False(t, found)
+	_, found = om.Front()
+	assert.False(t, found)
+	_, found = om.Back()
+	assert.False(t, found)
+
+	assert.True(t, om.Set("c", 3))
+	assert.True(t, om.Set("a", 1))
+	assert.True(t, om.Set("b", 2))
+	assert.False(t, om.Set("c", 30))
+	assert.Equal(t, 3, om.Len())
+	assert.Equal(t, []string{"c", "a", "b"}, om.Keys())
+	assert.Equal(t, []int{30, 1, 2}, om.Values())
+
+	value, found := om.Get("c")
+	assert.True(t, found)
+	assert.Equal(t, 30, value)
+	assert.True(t, om.Has("a"))
+
+	// move
+	assert.True(t, om.MoveToBack("c"))
+	assert.Equal(t, []string{"a", "b", "c"}, om.Keys())
+	assert.True(t, om.MoveToFront("b"))
+	assert.Equal(t, []string{"b", "a", "c"}, om.Keys())
+	assert.True(t, om.MoveToFront("b"))
+	assert.True(t, om.MoveToBack("c"))
+	assert.False(t, om.MoveToFront("z"))
+	assert.False(t, om.MoveToBack("z"))
+
+	front, _ := om.Front()
+	assert.Equal(t, MapEntry[string, int]{Key: "b", Value: 2}, front)
+	back, _ := om.Back()
+	assert.Equal(t, MapEntry[string, int]{Key: "c", Value: 30}, back)
+
+	// delete
+	assert.True(t, om.Delete("a"))
+	assert.False(t, om.Delete("a"))
+	assert.Equal(t, []string{"b", "c"}, om.Keys())
+	assert.True(t, om.Delete("b"))
+	assert.True(t, om.Delete("c"))
+	assert.Equal(t, []string{}, om.Keys())
+
+	om.Set("x", 1)
+	assert.Equal(t, []string{"x"}, om.Keys())
+	om.Clear()
+	assert.Equal(t, 0, om.Len())
+}
+
+func TestOrderedMapIteration(t *testing.T) {
+	om := NewOrderedMap[int, string]()
+	om.Set(3, "three")
+	om.Set(1, "one")
+	om.Set(2, "two")
+
+	expected := []MapEntry[int, string]{{3, "three"}, {1, "one"}, {2, "two"}}
+	assert.Equal(t, expected, om.Entries())
+	assert.Equal(t, expected, NewStream(om.Iterator()).Collect())
+
+	keys := make([]int, 0)
+	om.ForEach(func(key int, value string) bool {
+		keys = append(keys, key)
+		return key != 1
+	})
+	assert.Equal(t, []int{3, 1}, keys)
+
+	// interop with the map helpers
+	plain := om.ToMap()
+	assert.True(t, IsMap(plain))
+	assert.Equal(t, []int{1, 2, 3}, MapKeysSorted(plain))
+	assert.Equal(t, []int{1, 2, 3}, NewOrderedMapFromMap(plain).Keys())
+
+	assert.True(t, IsOrderedMap(om))
+	assert.False(t, IsOrderedMap(plain))
+	assert.False(t, IsOrderedMap(nil))
+	var missing *OrderedMap[int, string]
+	assert.False(t, IsOrderedMap(missing))
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	om := NewOrderedMap[string, interface{}]()
+	om.Set("zeta", 1)
+	om.Set("alpha", []int{1, 2})
+	om.Set("mid", map[string]string{"k": "v"})
+
+	data, err := json.Marshal(om)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"zeta":1,"alpha":[1,2],"mid":{"k":"v"}}`, string(data))
+
+	decoded := NewOrderedMap[string, int]()
+	assert.NoError(t, json.Unmarshal([]byte(`{"z":1,"a":2,"m":3,"a":4}`), decoded))
+	assert.Equal(t, []string{"z", "a", "m"}, decoded.Keys())
+	assert.Equal(t, []int{1, 4, 3}, decoded.Values())
+
+	// round trip
+	data, err = json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"z":1,"a":4,"m":3}`, string(data))
+
+	// nested in a struct
+	type config struct {
+		Values *OrderedMap[int, bool] `json:"values"`
+	}
+
+	var c config
+	assert.NoError(t, json.Unmarshal([]byte(`{"values":{"5":true,"2":false}}`), &c))
+	assert.Equal(t, []int{5, 2}, c.Values.Keys())
+	data, err = json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"values":{"5":true,"2":false}}`, string(data))
+
+	// null and errors
+	assert.NoError(t, json.Unmarshal([]byte(`null`), decoded))
+	assert.Equal(t, 0, decoded.Len())
+	assert.Error(t, json.Unmarshal([]byte(`[1]`), decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"a":"x"}`), decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"x":true}`), c.Values))
+
+	unsupported := NewOrderedMap[float64, int]()
+	unsupported.Set(1.5, 1)
+	_, err = json.Marshal(unsupported)
+	assert.Error(t, err)
+}
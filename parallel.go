@@ -0,0 +1,330 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//
+// Defines how the parallel functions react to an error
+// returned by the function called for an element.
+//
+type ParallelErrorPolicy int
+
+const (
+	ParallelFirstError ParallelErrorPolicy = iota // stop at the first error and return it
+	ParallelCollectAll                            // process all elements and return every error
+)
+
+//
+// Options for the parallel slice functions. A `nil` options
+// value uses one worker per CPU and the `ParallelFirstError`
+// policy.
+//
+type ParallelOptions struct {
+	Workers     int                 // number of concurrent workers, defaults to `runtime.NumCPU()`
+	ErrorPolicy ParallelErrorPolicy // how to handle errors
+}
+
+//
+// Error returned when the function called for an element
+// returns an error or panics. `Index` is the index of the
+// element in the slice.
+//
+type ParallelError struct {
+	Index int
+	Err   error
+}
+
+//
+// Return the error message.
+//
+func (e *ParallelError) Error() string {
+	return fmt.Sprintf("Element at index %d failed: %s", e.Index, e.Err.Error())
+}
+
+//
+// Return the wrapped error.
+//
+func (e *ParallelError) Unwrap() error {
+	return e.Err
+}
+
+//
+// All errors collected with the `ParallelCollectAll` policy,
+// sorted by the index of the element.
+//
+type ParallelErrors []*ParallelError
+
+//
+// Return the error message combining all errors.
+//
+func (e ParallelErrors) Error() string {
+	messages := make([]string, len(e))
+	for index, err := range e {
+		messages[index] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// return the number of workers to use for the given number of items
+func (options *ParallelOptions) workers(items int) int {
+	workers := 0
+	if options != nil {
+		workers = options.Workers
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if workers > items {
+		workers = items
+	}
+
+	return workers
+}
+
+// return the error policy to use
+func (options *ParallelOptions) errorPolicy() ParallelErrorPolicy {
+	if options == nil {
+		return ParallelFirstError
+	}
+
+	return options.ErrorPolicy
+}
+
+// call the function for the element recovering from any panic
+func parallelCall(index int, fn func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = &ParallelError{Index: index, Err: fmt.Errorf("panic: %v", recovered)}
+		}
+	}()
+
+	if err := fn(); err != nil {
+		if parallelErr, ok := err.(*ParallelError); ok {
+			return parallelErr
+		}
+		return &ParallelError{Index: index, Err: err}
+	}
+
+	return nil
+}
+
+//
+// Run the task for every index from `0` to `count - 1` on a
+// bounded pool of workers. The context passed to the task is
+// cancelled on the first error with `ParallelFirstError`, and
+// only that error is returned, not the errors that tasks still
+// running return for the cancellation. Returns the errors as per
+// the policy, else the context error if the context was cancelled
+// before all indexes were run.
+//
+func parallelRun(ctx context.Context, count int, options *ParallelOptions, task func(ctx context.Context, index int) error) error {
+	if count == 0 {
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	policy := options.errorPolicy()
+	indexes := make(chan int)
+	var mutex sync.Mutex
+	var first *ParallelError
+	var errs ParallelErrors
+
+	var wg sync.WaitGroup
+	workers := options.workers(count)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				err := parallelCall(index, func() error {
+					return task(ctx, index)
+				})
+				if err == nil {
+					continue
+				}
+
+				mutex.Lock()
+				if policy != ParallelFirstError {
+					errs = append(errs, err.(*ParallelError))
+				} else if first == nil {
+					// record the error before cancelling the others
+					first = err.(*ParallelError)
+					cancel()
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	// feed the indexes until done or cancelled
+	cancelled := false
+	for index := 0; index < count && !cancelled; index++ {
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+			cancelled = true
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	if first != nil {
+		return first
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Index < errs[j].Index
+		})
+		return errs
+	}
+
+	if cancelled {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+//
+// Return a new slice with the result of calling the mapper on
+// each element of the slice, running up to `Workers` mappers at
+// the same time. The order of the elements is preserved. If the
+// mapper returns an error or panics, a `*ParallelError` is returned
+// for `ParallelFirstError` and `ParallelErrors` for
+// `ParallelCollectAll`; the result is returned in both cases with
+// zero values for the failed or skipped elements. Returns `nil`
+// if the slice is `nil`.
+//
+func ParallelMap[T any, R any](ctx context.Context, slice []T, mapper func(ctx context.Context, item T) (R, error), options *ParallelOptions) ([]R, error) {
+	if slice == nil {
+		return nil, nil
+	}
+
+	result := make([]R, len(slice))
+	err := parallelRun(ctx, len(slice), options, func(ctx context.Context, index int) error {
+		value, err := mapper(ctx, slice[index])
+		if err != nil {
+			return err
+		}
+
+		result[index] = value
+		return nil
+	})
+
+	return result, err
+}
+
+//
+// Return a new slice with only the elements for which the
+// predicate returns `true`, running up to `Workers` predicates at
+// the same time. The order of the elements is preserved. Errors
+// are handled the same way as in `ParallelMap`. Returns `nil` if
+// the slice is `nil`.
+//
+func ParallelFilter[T any](ctx context.Context, slice []T, predicate func(ctx context.Context, item T) (bool, error), options *ParallelOptions) ([]T, error) {
+	if slice == nil {
+		return nil, nil
+	}
+
+	keep, err := ParallelMap(ctx, slice, predicate, options)
+	result := make([]T, 0, len(slice))
+	for index, item := range slice {
+		if keep[index] {
+			result = append(result, item)
+		}
+	}
+
+	return result, err
+}
+
+//
+// Call the consumer for each element of the slice, running up
+// to `Workers` consumers at the same time. Errors are handled
+// the same way as in `ParallelMap`.
+//
+func ParallelForEach[T any](ctx context.Context, slice []T, consumer func(ctx context.Context, item T) error, options *ParallelOptions) error {
+	return parallelRun(ctx, len(slice), options, func(ctx context.Context, index int) error {
+		return consumer(ctx, slice[index])
+	})
+}
+
+//
+// Reduce the slice to a single value in parallel. The slice is
+// split into one chunk per worker, each chunk is reduced starting
+// from `identity`, and the partial results are then combined in
+// order using the `combiner`. The `identity` must not change the
+// result when combined, such as `0` for a sum. Errors are handled
+// the same way as in `ParallelMap`.
+//
+func ParallelReduce[T any, R any](ctx context.Context, slice []T, identity R, reducer func(accumulated R, item T) (R, error), combiner func(left R, right R) R, options *ParallelOptions) (R, error) {
+	if len(slice) == 0 {
+		return identity, nil
+	}
+
+	workers := options.workers(len(slice))
+	size := (len(slice) + workers - 1) / workers
+	chunks := (len(slice) + size - 1) / size
+
+	partials := make([]R, chunks)
+	err := parallelRun(ctx, chunks, options, func(ctx context.Context, chunk int) error {
+		start := chunk * size
+		end := start + size
+		if end > len(slice) {
+			end = len(slice)
+		}
+
+		accumulated := identity
+		for index := start; index < end; index++ {
+			if err := ctx.Err(); err != nil {
+				return &ParallelError{Index: index, Err: err}
+			}
+
+			err := parallelCall(index, func() error {
+				var err error
+				accumulated, err = reducer(accumulated, slice[index])
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		partials[chunk] = accumulated
+		return nil
+	})
+
+	if err != nil {
+		return identity, err
+	}
+
+	result := identity
+	for _, partial := range partials {
+		result = combiner(result, partial)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,237 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelMap(t *testing.T) {
+	ctx := context.Background()
+	double := func(ctx context.Context, x int) (int, error) {
+		return x * 2, nil
+	}
+
+	// nil
+	var foo []int
+	result, err := ParallelMap(ctx, foo, double, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	// order is preserved
+	foo = make([]int, 100)
+	expected := make([]int, 100)
+	for index := range foo {
+		foo[index] = index
+		expected[index] = index * 2
+	}
+
+	result, err = ParallelMap(ctx, foo, double, &ParallelOptions{Workers: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	// concurrency is bounded
+	var running int32
+	var peak int32
+	_, err = ParallelMap(ctx, foo, func(ctx context.Context, x int) (int, error) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return x, nil
+	}, &ParallelOptions{Workers: 3})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, peak, int32(3))
+}
+
+func TestParallelMapErrors(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("odd")
+	evenOnly := func(ctx context.Context, x int) (int, error) {
+		if x%2 == 1 {
+			return 0, failure
+		}
+		return x, nil
+	}
+
+	foo := []int{0, 1, 2, 3, 4, 5}
+
+	// first error
+	_, err := ParallelMap(ctx, foo, evenOnly, &ParallelOptions{Workers: 1})
+	assert.True(t, errors.Is(err, failure))
+	var parallelErr *ParallelError
+	assert.True(t, errors.As(err, &parallelErr))
+	assert.Equal(t, 1, parallelErr.Index)
+
+	// collect all
+	result, err := ParallelMap(ctx, foo, evenOnly, &ParallelOptions{Workers: 4, ErrorPolicy: ParallelCollectAll})
+	assert.Equal(t, []int{0, 0, 2, 0, 4, 0}, result)
+	var all ParallelErrors
+	assert.True(t, errors.As(err, &all))
+	assert.Equal(t, 3, len(all))
+	assert.Equal(t, 1, all[0].Index)
+	assert.Equal(t, 3, all[1].Index)
+	assert.Equal(t, 5, all[2].Index)
+
+	// panics are recovered
+	_, err = ParallelMap(ctx, foo, func(ctx context.Context, x int) (int, error) {
+		if x == 4 {
+			panic("boom")
+		}
+		return x, nil
+	}, nil)
+	assert.True(t, errors.As(err, &parallelErr))
+	assert.Equal(t, 4, parallelErr.Index)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestParallelMapCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int32
+	foo := make([]int, 1000)
+	_, err := ParallelMap(ctx, foo, func(ctx context.Context, x int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return x, ctx.Err()
+	}, &ParallelOptions{Workers: 2})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Less(t, calls, int32(1000))
+}
+
+func TestParallelFilter(t *testing.T) {
+	ctx := context.Background()
+	even := func(ctx context.Context, x int) (bool, error) {
+		return x%2 == 0, nil
+	}
+
+	result, err := ParallelFilter(ctx, []int(nil), even, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = ParallelFilter(ctx, []int{1, 2, 3, 4, 6}, even, &ParallelOptions{Workers: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 6}, result)
+}
+
+func TestParallelForEach(t *testing.T) {
+	ctx := context.Background()
+	var sum int64
+	err := ParallelForEach(ctx, []int64{1, 2, 3, 4}, func(ctx context.Context, x int64) error {
+		atomic.AddInt64(&sum, x)
+		return nil
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), sum)
+
+	assert.NoError(t, ParallelForEach(ctx, []int64{}, func(ctx context.Context, x int64) error {
+		return errors.New("never called")
+	}, nil))
+}
+
+func TestParallelReduce(t *testing.T) {
+	ctx := context.Background()
+	add := func(total int, x int) (int, error) {
+		return total + x, nil
+	}
+	combine := func(left int, right int) int {
+		return left + right
+	}
+
+	sum, err := ParallelReduce(ctx, []int{}, 0, add, combine, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sum)
+
+	foo := make([]int, 101)
+	for index := range foo {
+		foo[index] = index
+	}
+
+	sum, err = ParallelReduce(ctx, foo, 0, add, combine, &ParallelOptions{Workers: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 5050, sum)
+
+	// order of combination is preserved
+	concat, err := ParallelReduce(ctx, []string{"a", "b", "c", "d", "e"}, "", func(total string, x string) (string, error) {
+		return total + x, nil
+	}, func(left string, right string) string {
+		return left + right
+	}, &ParallelOptions{Workers: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "abcde", concat)
+
+	// errors report the element index
+	_, err = ParallelReduce(ctx, foo, 0, func(total int, x int) (int, error) {
+		if x == 42 {
+			return 0, errors.New("bad")
+		}
+		return total + x, nil
+	}, combine, &ParallelOptions{Workers: 4})
+	var parallelErr *ParallelError
+	assert.True(t, errors.As(err, &parallelErr))
+	assert.Equal(t, 42, parallelErr.Index)
+}
+
+func TestParallelFirstErrorWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("bad")
+	foo := make([]int, 40)
+	for index := range foo {
+		foo[index] = index
+	}
+
+	// a later chunk fails while the earlier chunks are still running
+	_, err := ParallelReduce(ctx, foo, 0, func(total int, x int) (int, error) {
+		if x == 35 {
+			return 0, failure
+		}
+		if x < 30 {
+			time.Sleep(time.Millisecond)
+		}
+		return total + x, nil
+	}, func(left int, right int) int {
+		return left + right
+	}, &ParallelOptions{Workers: 4})
+	var parallelErr *ParallelError
+	assert.True(t, errors.As(err, &parallelErr))
+	assert.Equal(t, 35, parallelErr.Index)
+	assert.True(t, errors.Is(err, failure))
+	assert.False(t, errors.Is(err, context.Canceled))
+
+	// tasks returning the context error after the failure
+	_, err = ParallelMap(ctx, foo, func(ctx context.Context, x int) (int, error) {
+		if x == 3 {
+			return 0, failure
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return x, nil
+		}
+	}, &ParallelOptions{Workers: 4})
+	assert.True(t, errors.As(err, &parallelErr))
+	assert.Equal(t, 3, parallelErr.Index)
+	assert.True(t, errors.Is(err, failure))
+}
@@ -0,0 +1,534 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// Returned by the path functions when the path cannot
+// be found in the data.
+//
+var ErrPathNotFound = errors.New("Path not found")
+
+// a single parsed segment of a path
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// index represented by this segment, either as `[0]` or as `.0`
+func (segment pathSegment) asIndex() (int, bool) {
+	if segment.isIndex {
+		return segment.index, true
+	}
+
+	index, err := strconv.Atoi(segment.key)
+	if err != nil || index < 0 {
+		return 0, false
+	}
+
+	return index, true
+}
+
+//
+// Parse a path like `servers[0].ports.http` into its segments.
+// Keys are separated by a `.`, slice indexes are written as `[0]`
+// and keys containing special characters can be quoted as `["a.b"]`.
+// A `*` or `[*]` segment matches every element.
+//
+func parsePath(path string) ([]pathSegment, error) {
+	segments := make([]pathSegment, 0)
+	if path == "" {
+		return segments, nil
+	}
+
+	length := len(path)
+	index := 0
+	for index < length {
+		char := path[index]
+
+		switch {
+		case char == '[':
+			end := strings.IndexByte(path[index:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("Unclosed bracket in path: %s", path)
+			}
+
+			content := path[index+1 : index+end]
+			index += end + 1
+
+			segment, err := parseBracketSegment(content)
+			if err != nil {
+				return nil, fmt.Errorf("%s in path: %s", err.Error(), path)
+			}
+			segments = append(segments, segment)
+
+			// a bracket may be followed by another bracket, a dot or the end
+			if index < length {
+				if path[index] == '.' {
+					index++
+					if index == length {
+						return nil, fmt.Errorf("Path cannot end with a dot: %s", path)
+					}
+				} else if path[index] != '[' {
+					return nil, fmt.Errorf("Unexpected character after bracket in path: %s", path)
+				}
+			}
+
+		default:
+			end := strings.IndexAny(path[index:], ".[")
+			if end < 0 {
+				end = length - index
+			}
+
+			key := path[index : index+end]
+			if key == "" {
+				return nil, fmt.Errorf("Empty key in path: %s", path)
+			}
+
+			if key == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				segments = append(segments, pathSegment{key: key})
+			}
+
+			index += end
+			if index < length && path[index] == '.' {
+				index++
+				if index == length {
+					return nil, fmt.Errorf("Path cannot end with a dot: %s", path)
+				}
+			}
+		}
+	}
+
+	return segments, nil
+}
+
+// parse the content between `[` and `]`
+func parseBracketSegment(content string) (pathSegment, error) {
+	if content == "*" {
+		return pathSegment{wildcard: true}, nil
+	}
+
+	if len(content) >= 2 && (content[0] == '"' || content[0] == '\'') && content[len(content)-1] == content[0] {
+		return pathSegment{key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return pathSegment{}, fmt.Errorf("Invalid index [%s]", content)
+	}
+
+	return pathSegment{index: index, isIndex: true}, nil
+}
+
+//
+// Read the child addressed by the segment from the container.
+// Maps with string keys, slices and arrays are supported.
+//
+func pathChild(container interface{}, segment pathSegment) (interface{}, bool) {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		if segment.isIndex {
+			return nil, false
+		}
+		value, found := c[segment.key]
+		return value, found
+
+	case []interface{}:
+		index, ok := segment.asIndex()
+		if !ok || index >= len(c) {
+			return nil, false
+		}
+		return c[index], true
+	}
+
+	if IsMap(container) {
+		reflected := reflect.ValueOf(container)
+		if segment.isIndex || reflected.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		value := reflected.MapIndex(reflect.ValueOf(segment.key).Convert(reflected.Type().Key()))
+		if !value.IsValid() {
+			return nil, false
+		}
+		return value.Interface(), true
+	}
+
+	if IsSlice(container) || (container != nil && reflect.TypeOf(container).Kind() == reflect.Array) {
+		reflected := reflect.ValueOf(container)
+		index, ok := segment.asIndex()
+		if !ok || index >= reflected.Len() {
+			return nil, false
+		}
+		return reflected.Index(index).Interface(), true
+	}
+
+	return nil, false
+}
+
+//
+// Return all children of the container for a wildcard segment.
+// Map values are returned in the sorted order of their keys.
+//
+func pathChildren(container interface{}) []interface{} {
+	if container == nil {
+		return nil
+	}
+
+	reflected := reflect.ValueOf(container)
+	switch reflected.Kind() {
+	case reflect.Map:
+		keys := reflected.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		children := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			children = append(children, reflected.MapIndex(key).Interface())
+		}
+		return children
+
+	case reflect.Slice, reflect.Array:
+		children := make([]interface{}, 0, reflected.Len())
+		for index := 0; index < reflected.Len(); index++ {
+			children = append(children, reflected.Index(index).Interface())
+		}
+		return children
+	}
+
+	return nil
+}
+
+//
+// Return the value at the given path in nested maps and slices,
+// such as those obtained by parsing JSON or YAML. For example,
+// `GetPath(data, "servers[0].ports.http")`. An empty path returns
+// the data itself. Returns `ErrPathNotFound` if any segment of
+// the path cannot be found, and an `error` if the path is invalid
+// or contains a wildcard.
+//
+func GetPath(data interface{}, path string) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := data
+	for _, segment := range segments {
+		if segment.wildcard {
+			return nil, errors.New("Wildcards are not supported in GetPath, use QueryPath instead")
+		}
+
+		child, found := pathChild(current, segment)
+		if !found {
+			return nil, ErrPathNotFound
+		}
+		current = child
+	}
+
+	return current, nil
+}
+
+//
+// Return the value at the given path converted to `int64` using
+// `ConvertToInt64`. Returns the `defaultValue` if the path cannot
+// be found.
+//
+func GetPathInt64(data interface{}, path string, defaultValue int64) (int64, error) {
+	value, err := GetPath(data, path)
+	if err == ErrPathNotFound {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return defaultValue, err
+	}
+
+	return ConvertToInt64(value, defaultValue)
+}
+
+//
+// Return the value at the given path converted to `float64` using
+// `ConvertToFloat64`. Returns the `defaultValue` if the path cannot
+// be found.
+//
+func GetPathFloat64(data interface{}, path string, defaultValue float64) (float64, error) {
+	value, err := GetPath(data, path)
+	if err == ErrPathNotFound {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return defaultValue, err
+	}
+
+	return ConvertToFloat64(value, defaultValue)
+}
+
+//
+// Return the value at the given path converted to `bool` using
+// `ConvertToBool`. Returns the `defaultValue` if the path cannot
+// be found or its value is `nil`.
+//
+func GetPathBool(data interface{}, path string, defaultValue bool) (bool, error) {
+	value, err := GetPath(data, path)
+	if err == ErrPathNotFound {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return defaultValue, err
+	}
+
+	if isNilValue(value) {
+		return defaultValue, nil
+	}
+
+	return ConvertToBool(value)
+}
+
+//
+// Return the value at the given path converted to `string` using
+// `ConvertToString`. Returns the `defaultValue` if the path cannot
+// be found or is invalid.
+//
+func GetPathString(data interface{}, path string, defaultValue string) string {
+	value, err := GetPath(data, path)
+	if err != nil {
+		return defaultValue
+	}
+
+	return ConvertToString(value)
+}
+
+//
+// Return all values matching the given path. The path may contain
+// `*` or `[*]` wildcards, for example `servers[*].name`, which
+// match every element of a slice or every value of a map. Branches
+// where the path cannot be found are skipped. Returns an empty
+// slice if nothing matches, and an `error` if the path is invalid.
+//
+func QueryPath(data interface{}, path string) ([]interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []interface{}{data}
+	for _, segment := range segments {
+		next := make([]interface{}, 0, len(current))
+		for _, item := range current {
+			if segment.wildcard {
+				next = append(next, pathChildren(item)...)
+				continue
+			}
+
+			if child, found := pathChild(item, segment); found {
+				next = append(next, child)
+			}
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+//
+// Set the value at the given path in the nested map, creating
+// intermediate maps and slices as needed. A `[n]` segment creates
+// a `[]interface{}` or replaces one of its elements, and appends an
+// element if `n` is its length; any other segment creates a
+// `map[string]interface{}`. Returns an `error` if the data is
+// `nil`, the path is empty, invalid or contains a wildcard, if an
+// index is beyond the end of a slice, or if an existing
+// intermediate value is neither a map nor a slice.
+//
+func SetPath(data map[string]interface{}, path string, value interface{}) error {
+	if data == nil {
+		return errors.New("Cannot set path on a nil map")
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 {
+		return errors.New("Path cannot be empty")
+	}
+
+	if segments[0].isIndex {
+		return errors.New("Path must start with a key")
+	}
+
+	if hasWildcard(segments) {
+		return errors.New("Wildcards are not supported in SetPath")
+	}
+
+	_, err = setPathValue(data, segments, value, path)
+	return err
+}
+
+// recursively set the value and return the updated container
+func setPathValue(container interface{}, segments []pathSegment, value interface{}, path string) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment := segments[0]
+
+	// existing slices can also be addressed with `.0` segments
+	list, isList := container.([]interface{})
+	if segment.isIndex || isList {
+		index, ok := segment.asIndex()
+		if !ok {
+			return nil, fmt.Errorf("Cannot use key %s on a slice in path: %s", segment.key, path)
+		}
+
+		if container != nil && !isList {
+			return nil, fmt.Errorf("Value at index [%d] is not a slice in path: %s", index, path)
+		}
+
+		if index > len(list) {
+			return nil, fmt.Errorf("Index [%d] is beyond the end of the slice in path: %s", index, path)
+		}
+
+		if index == len(list) {
+			list = append(list, nil)
+		}
+
+		child, err := setPathValue(list[index], segments[1:], value, path)
+		if err != nil {
+			return nil, err
+		}
+
+		list[index] = child
+		return list, nil
+	}
+
+	var m map[string]interface{}
+	switch c := container.(type) {
+	case nil:
+		m = make(map[string]interface{})
+
+	case map[string]interface{}:
+		m = c
+
+	default:
+		return nil, fmt.Errorf("Value at key %s is not a map in path: %s", segment.key, path)
+	}
+
+	child, err := setPathValue(m[segment.key], segments[1:], value, path)
+	if err != nil {
+		return nil, err
+	}
+
+	m[segment.key] = child
+	return m, nil
+}
+
+//
+// Delete the value at the given path from the nested map. Slice
+// elements are removed and the following elements shifted. Returns
+// `true` if a value was deleted, `false` if the path could not be
+// found, and an `error` if the path is empty, invalid or contains
+// a wildcard.
+//
+func DeletePath(data map[string]interface{}, path string) (bool, error) {
+	if data == nil {
+		return false, nil
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return false, err
+	}
+
+	if len(segments) == 0 {
+		return false, errors.New("Path cannot be empty")
+	}
+
+	if hasWildcard(segments) {
+		return false, errors.New("Wildcards are not supported in DeletePath")
+	}
+
+	_, deleted := deletePathValue(data, segments)
+	return deleted, nil
+}
+
+// check if any of the segments is a wildcard
+func hasWildcard(segments []pathSegment) bool {
+	for _, segment := range segments {
+		if segment.wildcard {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recursively delete the value and return the updated container
+func deletePathValue(container interface{}, segments []pathSegment) (interface{}, bool) {
+	segment := segments[0]
+
+	last := len(segments) == 1
+	switch c := container.(type) {
+	case map[string]interface{}:
+		if segment.isIndex {
+			return container, false
+		}
+
+		child, found := c[segment.key]
+		if !found {
+			return container, false
+		}
+
+		if last {
+			delete(c, segment.key)
+			return c, true
+		}
+
+		updated, deleted := deletePathValue(child, segments[1:])
+		if deleted {
+			c[segment.key] = updated
+		}
+		return c, deleted
+
+	case []interface{}:
+		index, ok := segment.asIndex()
+		if !ok || index >= len(c) {
+			return container, false
+		}
+
+		if last {
+			return append(c[:index], c[index+1:]...), true
+		}
+
+		updated, deleted := deletePathValue(c[index], segments[1:])
+		if deleted {
+			c[index] = updated
+		}
+		return c, deleted
+	}
+
+	return container, false
+}
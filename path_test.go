@@ -0,0 +1,243 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getPathTestData() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "cluster",
+		"servers": []interface{}{
+			map[string]interface{}{
+				"name":    "alpha",
+				"enabled": "true",
+				"ports": map[string]interface{}{
+					"http": "8080",
+				},
+			},
+			map[string]interface{}{
+				"name":    "beta",
+				"enabled": false,
+				"ports": map[string]interface{}{
+					"http": 9090,
+				},
+			},
+		},
+		"tags":    []string{"a", "b"},
+		"weights": map[string]float64{"x": 1.5},
+		"dotted":  map[string]interface{}{"a.b": 1},
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	segments, err := parsePath("servers[0].ports.http")
+	assert.NoError(t, err)
+	assert.Equal(t, []pathSegment{{key: "servers"}, {index: 0, isIndex: true}, {key: "ports"}, {key: "http"}}, segments)
+
+	segments, err = parsePath("a[*].b.*[\"c.d\"]")
+	assert.NoError(t, err)
+	assert.Equal(t, []pathSegment{{key: "a"}, {wildcard: true}, {key: "b"}, {wildcard: true}, {key: "c.d"}}, segments)
+
+	// invalid
+	for _, path := range []string{".a", "a.", "a..b", "a[", "a[x]", "a[-1]", "a[0]b", "a[0]."} {
+		_, err = parsePath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	data := getPathTestData()
+
+	value, err := GetPath(data, "servers[0].ports.http")
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", value)
+
+	value, err = GetPath(data, "servers.1.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "beta", value)
+
+	value, err = GetPath(data, "tags[1]")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", value)
+
+	value, err = GetPath(data, "weights.x")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, value)
+
+	value, err = GetPath(data, "dotted['a.b']")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = GetPath(data, "")
+	assert.NoError(t, err)
+	assert.Equal(t, data, value)
+
+	// not found
+	_, err = GetPath(data, "servers[5].name")
+	assert.Equal(t, ErrPathNotFound, err)
+	_, err = GetPath(data, "name.first")
+	assert.Equal(t, ErrPathNotFound, err)
+	_, err = GetPath(nil, "name")
+	assert.Equal(t, ErrPathNotFound, err)
+
+	// wildcard
+	_, err = GetPath(data, "servers[*].name")
+	assert.Error(t, err)
+}
+
+func TestGetPathTyped(t *testing.T) {
+	data := getPathTestData()
+
+	number, err := GetPathInt64(data, "servers[0].ports.http", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), number)
+
+	number, err = GetPathInt64(data, "servers[1].ports.http", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9090), number)
+
+	number, err = GetPathInt64(data, "servers[2].ports.http", 80)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(80), number)
+
+	_, err = GetPathInt64(data, "name", 0)
+	assert.Error(t, err)
+
+	float, err := GetPathFloat64(data, "weights.x", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, float)
+
+	b, err := GetPathBool(data, "servers[0].enabled", false)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = GetPathBool(data, "servers[1].enabled", true)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	b, err = GetPathBool(data, "servers[9].enabled", false)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	b, err = GetPathBool(data, "servers[9].enabled", true)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	assert.Equal(t, "alpha", GetPathString(data, "servers[0].name", ""))
+	assert.Equal(t, "none", GetPathString(data, "servers[0].missing", "none"))
+}
+
+func TestQueryPath(t *testing.T) {
+	data := getPathTestData()
+
+	values, err := QueryPath(data, "servers[*].name")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"alpha", "beta"}, values)
+
+	values, err = QueryPath(data, "servers.*.ports.*")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"8080", 9090}, values)
+
+	values, err = QueryPath(data, "servers[*].missing")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(values))
+
+	_, err = QueryPath(data, "servers[")
+	assert.Error(t, err)
+}
+
+func TestSetPath(t *testing.T) {
+	assert.Error(t, SetPath(nil, "a", 1))
+
+	data := map[string]interface{}{}
+	assert.NoError(t, SetPath(data, "a.b.c", 1))
+	assert.NoError(t, SetPath(data, "list[0]", "first"))
+	assert.NoError(t, SetPath(data, "list.1", "second"))
+	assert.NoError(t, SetPath(data, "list[2].name", "third"))
+
+	expected := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{
+				"c": 1,
+			},
+		},
+		"list": []interface{}{
+			"first",
+			"second",
+			map[string]interface{}{"name": "third"},
+		},
+	}
+	assert.Equal(t, expected, data)
+
+	// overwrite
+	assert.NoError(t, SetPath(data, "a.b", "leaf"))
+	assert.Equal(t, "leaf", GetPathString(data, "a.b", ""))
+
+	// errors
+	assert.Error(t, SetPath(data, "", 1))
+	assert.Error(t, SetPath(data, "[0]", 1))
+	assert.Error(t, SetPath(data, "a.b.c", 1))
+	assert.Error(t, SetPath(data, "a[0]", 1))
+	assert.Error(t, SetPath(data, "list.x", 1))
+	assert.Error(t, SetPath(data, "list[*]", 1))
+
+	// slices only grow by one element
+	assert.Error(t, SetPath(data, "list[4]", 1))
+	assert.Error(t, SetPath(data, "list.1000000000", 1))
+	assert.Error(t, SetPath(data, "fresh[1].x", 1))
+	assert.Equal(t, 3, len(data["list"].([]interface{})))
+	_, found := data["fresh"]
+	assert.False(t, found)
+
+	// wildcards are rejected before anything is created
+	assert.Error(t, SetPath(data, "fresh.*.x", 1))
+	_, found = data["fresh"]
+	assert.False(t, found)
+}
+
+func TestDeletePath(t *testing.T) {
+	data := getPathTestData()
+
+	deleted, err := DeletePath(data, "servers[0].ports.http")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = GetPath(data, "servers[0].ports.http")
+	assert.Equal(t, ErrPathNotFound, err)
+
+	deleted, err = DeletePath(data, "servers[0]")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, "beta", GetPathString(data, "servers[0].name", ""))
+	assert.Equal(t, 1, len(data["servers"].([]interface{})))
+
+	deleted, err = DeletePath(data, "missing.key")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = DeletePath(nil, "missing")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	_, err = DeletePath(data, "")
+	assert.Error(t, err)
+	_, err = DeletePath(data, "servers[*]")
+	assert.Error(t, err)
+
+	// wildcards are rejected even if the path does not exist
+	deleted, err = DeletePath(data, "missing.*")
+	assert.Error(t, err)
+	assert.False(t, deleted)
+}
@@ -0,0 +1,391 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+//
+// A handle to an element in a `PriorityQueue`. The handle
+// can be used to update the priority of the element or to
+// remove it from the queue.
+//
+type PriorityQueueHandle[T any] struct {
+	value T
+	queue *PriorityQueue[T]
+
+	// the position of the element in the heap of the queue, and
+	// in the heap of its lowest priority elements if bounded
+	index    int
+	lowIndex int
+}
+
+//
+// Return the value of the element.
+//
+func (handle *PriorityQueueHandle[T]) Value() T {
+	return handle.value
+}
+
+//
+// Check if the element is still present in its queue.
+//
+func (handle *PriorityQueueHandle[T]) InQueue() bool {
+	return handle.queue != nil
+}
+
+//
+// A priority queue backed by a binary heap. The `less` function
+// returns `true` if `a` has a higher priority than `b`, and the
+// element with the highest priority is returned first. A bounded
+// queue evicts its lowest priority element when a higher priority
+// element is pushed while it is full, in logarithmic time. The
+// queue is not safe for concurrent use.
+//
+type PriorityQueue[T any] struct {
+	heap     priorityHeap[T]
+	less     func(a T, b T) bool
+	capacity int
+	onEvict  func(value T)
+
+	// the same elements with the lowest priority at the root, kept
+	// only for bounded queues to find the element to evict
+	lowest *priorityHeap[T]
+}
+
+//
+// Create a new unbounded priority queue ordered by the
+// `less` function.
+//
+func NewPriorityQueue[T any](less func(a T, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		heap: priorityHeap[T]{
+			items: make([]*PriorityQueueHandle[T], 0),
+			less:  less,
+		},
+		less: less,
+	}
+}
+
+//
+// Create a new priority queue that holds at most `capacity`
+// elements. When full, pushing an element evicts the lowest
+// priority element, which may be the pushed element itself. The
+// `onEvict` function, if not `nil`, is called with each evicted
+// value. A `capacity` of less than one means unbounded.
+//
+func NewBoundedPriorityQueue[T any](capacity int, less func(a T, b T) bool, onEvict func(value T)) *PriorityQueue[T] {
+	queue := NewPriorityQueue(less)
+	queue.capacity = capacity
+	queue.onEvict = onEvict
+	if capacity > 0 {
+		queue.lowest = &priorityHeap[T]{
+			less: func(a T, b T) bool {
+				return less(b, a)
+			},
+			low: true,
+		}
+	}
+
+	return queue
+}
+
+//
+// Create a new priority queue that returns the smallest
+// value first.
+//
+func NewMinPriorityQueue[T Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueue(func(a T, b T) bool {
+		return a < b
+	})
+}
+
+//
+// Create a new priority queue that returns the largest
+// value first.
+//
+func NewMaxPriorityQueue[T Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueue(func(a T, b T) bool {
+		return a > b
+	})
+}
+
+//
+// Create a new unbounded priority queue containing the elements
+// of the slice. The heap is built in linear time. The slice is
+// not modified.
+//
+func NewPriorityQueueFromSlice[T any](slice []T, less func(a T, b T) bool) *PriorityQueue[T] {
+	queue := NewPriorityQueue(less)
+	queue.heap.items = make([]*PriorityQueueHandle[T], len(slice))
+	for index, value := range slice {
+		queue.heap.items[index] = &PriorityQueueHandle[T]{value: value, index: index, queue: queue}
+	}
+
+	for index := len(slice)/2 - 1; index >= 0; index-- {
+		queue.heap.down(index)
+	}
+
+	return queue
+}
+
+//
+// Return the number of elements in the queue.
+//
+func (queue *PriorityQueue[T]) Len() int {
+	return len(queue.heap.items)
+}
+
+//
+// Check if the queue has no elements.
+//
+func (queue *PriorityQueue[T]) IsEmpty() bool {
+	return len(queue.heap.items) == 0
+}
+
+//
+// Return the maximum number of elements the queue can hold,
+// or `0` if the queue is unbounded.
+//
+func (queue *PriorityQueue[T]) Capacity() int {
+	if queue.capacity < 1 {
+		return 0
+	}
+
+	return queue.capacity
+}
+
+//
+// Push the value in the queue and return its handle. If the
+// queue is full, the lowest priority element is evicted. Returns
+// `nil` if the pushed value itself was evicted.
+//
+func (queue *PriorityQueue[T]) Push(value T) *PriorityQueueHandle[T] {
+	if queue.lowest != nil && len(queue.heap.items) >= queue.capacity {
+		lowest := queue.lowest.items[0]
+		if !queue.less(value, lowest.value) {
+			queue.evict(value)
+			return nil
+		}
+
+		queue.removeAt(lowest.index)
+		queue.evict(lowest.value)
+	}
+
+	handle := &PriorityQueueHandle[T]{value: value, queue: queue}
+	queue.heap.push(handle)
+	if queue.lowest != nil {
+		queue.lowest.push(handle)
+	}
+
+	return handle
+}
+
+//
+// Return the highest priority value without removing it.
+// Returns `false` if the queue is empty.
+//
+func (queue *PriorityQueue[T]) Peek() (T, bool) {
+	if len(queue.heap.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return queue.heap.items[0].value, true
+}
+
+//
+// Remove and return the highest priority value. Returns
+// `false` if the queue is empty.
+//
+func (queue *PriorityQueue[T]) Pop() (T, bool) {
+	if len(queue.heap.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return queue.removeAt(0).value, true
+}
+
+//
+// Update the value of the element referred by the handle and
+// restore the heap order. Returns `false` if the element is not
+// in this queue.
+//
+func (queue *PriorityQueue[T]) Update(handle *PriorityQueueHandle[T], value T) bool {
+	if handle == nil || handle.queue != queue {
+		return false
+	}
+
+	handle.value = value
+	queue.heap.fix(handle.index)
+	if queue.lowest != nil {
+		queue.lowest.fix(handle.lowIndex)
+	}
+
+	return true
+}
+
+//
+// Remove the element referred by the handle from the queue.
+// Returns `false` if the element is not in this queue.
+//
+func (queue *PriorityQueue[T]) Remove(handle *PriorityQueueHandle[T]) bool {
+	if handle == nil || handle.queue != queue {
+		return false
+	}
+
+	queue.removeAt(handle.index)
+	return true
+}
+
+//
+// Return the values in the queue in heap order, which is not
+// the priority order. Use `PopAll` to get sorted values.
+//
+func (queue *PriorityQueue[T]) Values() []T {
+	values := make([]T, len(queue.heap.items))
+	for index, item := range queue.heap.items {
+		values[index] = item.value
+	}
+
+	return values
+}
+
+//
+// Remove all values from the queue and return them in
+// priority order, highest priority first.
+//
+func (queue *PriorityQueue[T]) PopAll() []T {
+	values := make([]T, 0, len(queue.heap.items))
+	for len(queue.heap.items) > 0 {
+		values = append(values, queue.removeAt(0).value)
+	}
+
+	return values
+}
+
+// call the eviction callback if set
+func (queue *PriorityQueue[T]) evict(value T) {
+	if queue.onEvict != nil {
+		queue.onEvict(value)
+	}
+}
+
+// remove the element at the index and return its handle
+func (queue *PriorityQueue[T]) removeAt(index int) *PriorityQueueHandle[T] {
+	handle := queue.heap.items[index]
+	queue.heap.remove(index)
+	if queue.lowest != nil {
+		queue.lowest.remove(handle.lowIndex)
+	}
+
+	handle.queue = nil
+	return handle
+}
+
+// a binary heap of handles, with the element for which `less`
+// holds against all others at the root. A queue orders its
+// elements by priority, tracked in the `index` of each handle,
+// and a bounded queue also by the reverse, tracked in the
+// `lowIndex` of each handle.
+type priorityHeap[T any] struct {
+	items []*PriorityQueueHandle[T]
+	less  func(a T, b T) bool
+	low   bool
+}
+
+// add the handle to the heap
+func (heap *priorityHeap[T]) push(handle *PriorityQueueHandle[T]) {
+	index := len(heap.items)
+	heap.items = append(heap.items, handle)
+	heap.setIndex(index)
+	heap.up(index)
+}
+
+// remove the handle at the index from the heap
+func (heap *priorityHeap[T]) remove(index int) {
+	last := len(heap.items) - 1
+	if index != last {
+		heap.swap(index, last)
+	}
+
+	if heap.low {
+		heap.items[last].lowIndex = -1
+	} else {
+		heap.items[last].index = -1
+	}
+
+	heap.items[last] = nil
+	heap.items = heap.items[:last]
+	if index != last {
+		heap.fix(index)
+	}
+}
+
+// restore heap order for the handle at the index
+func (heap *priorityHeap[T]) fix(index int) {
+	if !heap.down(index) {
+		heap.up(index)
+	}
+}
+
+// move the handle up towards the root
+func (heap *priorityHeap[T]) up(index int) {
+	for index > 0 {
+		parent := (index - 1) / 2
+		if !heap.less(heap.items[index].value, heap.items[parent].value) {
+			break
+		}
+
+		heap.swap(index, parent)
+		index = parent
+	}
+}
+
+// move the handle down towards the leaves, return `true` if moved
+func (heap *priorityHeap[T]) down(index int) bool {
+	start := index
+	length := len(heap.items)
+	for {
+		child := 2*index + 1
+		if child >= length {
+			break
+		}
+
+		if right := child + 1; right < length && heap.less(heap.items[right].value, heap.items[child].value) {
+			child = right
+		}
+
+		if !heap.less(heap.items[child].value, heap.items[index].value) {
+			break
+		}
+
+		heap.swap(index, child)
+		index = child
+	}
+
+	return index > start
+}
+
+// swap two handles and update their positions
+func (heap *priorityHeap[T]) swap(i int, j int) {
+	heap.items[i], heap.items[j] = heap.items[j], heap.items[i]
+	heap.setIndex(i)
+	heap.setIndex(j)
+}
+
+// record the position of the handle at the index
+func (heap *priorityHeap[T]) setIndex(index int) {
+	if heap.low {
+		heap.items[index].lowIndex = index
+	} else {
+		heap.items[index].index = index
+	}
+}
@@ -0,0 +1,210 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	queue := NewMinPriorityQueue[int]()
+	assert.True(t, queue.IsEmpty())
+	assert.Equal(t, 0, queue.Capacity())
+
+	_, ok := queue.Peek()
+	assert.False(t, ok)
+	_, ok = queue.Pop()
+	assert.False(t, ok)
+
+	for _, value := range []int{5, 3, 8, 1, 9, 2} {
+		queue.Push(value)
+	}
+	assert.Equal(t, 6, queue.Len())
+
+	value, ok := queue.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 6, queue.Len())
+
+	value, ok = queue.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, []int{2, 3, 5, 8, 9}, queue.PopAll())
+	assert.True(t, queue.IsEmpty())
+
+	max := NewMaxPriorityQueue[string]()
+	max.Push("b")
+	max.Push("c")
+	max.Push("a")
+	assert.Equal(t, []string{"c", "b", "a"}, max.PopAll())
+}
+
+func TestPriorityQueueRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	values := make([]int, 500)
+	for index := range values {
+		values[index] = random.Intn(1000)
+	}
+
+	queue := NewPriorityQueueFromSlice(values, func(a int, b int) bool {
+		return a < b
+	})
+	assert.Equal(t, len(values), len(queue.Values()))
+
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
+	assert.Equal(t, sorted, queue.PopAll())
+}
+
+func TestPriorityQueueHandles(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+
+	queue := NewPriorityQueue(func(a task, b task) bool {
+		return a.priority > b.priority
+	})
+
+	low := queue.Push(task{"low", 1})
+	medium := queue.Push(task{"medium", 5})
+	high := queue.Push(task{"high", 10})
+	assert.Equal(t, "medium", medium.Value().name)
+
+	// update priority
+	assert.True(t, queue.Update(low, task{"low", 20}))
+	top, _ := queue.Peek()
+	assert.Equal(t, "low", top.name)
+
+	assert.True(t, queue.Update(low, task{"low", 0}))
+	top, _ = queue.Peek()
+	assert.Equal(t, "high", top.name)
+
+	// remove
+	assert.True(t, queue.Remove(high))
+	assert.False(t, high.InQueue())
+	assert.False(t, queue.Remove(high))
+	assert.False(t, queue.Update(high, task{"high", 1}))
+	assert.False(t, queue.Remove(nil))
+
+	// popped handles are no longer valid
+	popped, _ := queue.Pop()
+	assert.Equal(t, "medium", popped.name)
+	assert.False(t, medium.InQueue())
+	assert.True(t, low.InQueue())
+
+	other := NewPriorityQueue(func(a task, b task) bool {
+		return a.priority > b.priority
+	})
+	assert.False(t, other.Remove(low))
+}
+
+func TestBoundedPriorityQueue(t *testing.T) {
+	// keep the top 3 largest values
+	evicted := make([]int, 0)
+	queue := NewBoundedPriorityQueue(3, func(a int, b int) bool {
+		return a > b
+	}, func(value int) {
+		evicted = append(evicted, value)
+	})
+	assert.Equal(t, 3, queue.Capacity())
+
+	assert.NotNil(t, queue.Push(5))
+	assert.NotNil(t, queue.Push(1))
+	assert.NotNil(t, queue.Push(7))
+
+	// lower than everything, rejected
+	assert.Nil(t, queue.Push(0))
+
+	// evicts 1
+	assert.NotNil(t, queue.Push(6))
+	assert.Equal(t, 3, queue.Len())
+
+	assert.Equal(t, []int{0, 1}, evicted)
+	assert.Equal(t, []int{7, 6, 5}, queue.PopAll())
+}
+
+func TestBoundedPriorityQueueRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	queue := NewBoundedPriorityQueue(16, func(a int, b int) bool {
+		return a < b
+	}, nil)
+
+	handles := make([]*PriorityQueueHandle[int], 0)
+	for round := 0; round < 2000; round++ {
+		switch random.Intn(4) {
+		case 0:
+			if len(handles) > 0 {
+				index := random.Intn(len(handles))
+				queue.Update(handles[index], random.Intn(1000))
+			}
+
+		case 1:
+			if len(handles) > 0 {
+				index := random.Intn(len(handles))
+				queue.Remove(handles[index])
+			}
+
+		default:
+			if handle := queue.Push(random.Intn(1000)); handle != nil {
+				handles = append(handles, handle)
+			}
+		}
+
+		// drop the handles of evicted and removed elements
+		kept := handles[:0]
+		for _, handle := range handles {
+			if handle.InQueue() {
+				kept = append(kept, handle)
+			}
+		}
+		handles = kept
+		assert.Equal(t, len(handles), queue.Len())
+		assert.LessOrEqual(t, queue.Len(), 16)
+	}
+
+	expected := make([]int, len(handles))
+	for index, handle := range handles {
+		expected[index] = handle.Value()
+	}
+	sort.Ints(expected)
+	assert.Equal(t, expected, queue.PopAll())
+}
+
+func TestBoundedPriorityQueueEviction(t *testing.T) {
+	comparisons := 0
+	queue := NewBoundedPriorityQueue(1024, func(a int, b int) bool {
+		comparisons++
+		return a > b
+	}, nil)
+
+	for value := 0; value < 1024; value++ {
+		queue.Push(value)
+	}
+
+	// each push into the full queue evicts the smallest value
+	comparisons = 0
+	for value := 1024; value < 2048; value++ {
+		queue.Push(value)
+	}
+	assert.Less(t, comparisons, 1024*4*10)
+
+	values := queue.PopAll()
+	assert.Equal(t, 1024, len(values))
+	assert.Equal(t, 2047, values[0])
+	assert.Equal(t, 1024, values[1023])
+}
@@ -0,0 +1,162 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+//
+// A fixed-capacity ring buffer. Once full, adding a value
+// overwrites the oldest value. Values are read in logical
+// order, oldest first. The zero value is an empty ring buffer
+// with a capacity of one, use `NewRingBuffer` for a larger one.
+// The ring buffer is not safe for concurrent use.
+//
+type RingBuffer[T any] struct {
+	buffer []T
+	head   int
+	count  int
+}
+
+//
+// Create a new ring buffer holding at most `capacity` values.
+// A `capacity` of less than one is treated as one.
+//
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &RingBuffer[T]{
+		buffer: make([]T, capacity),
+	}
+}
+
+//
+// Return the number of values in the ring buffer.
+//
+func (ring *RingBuffer[T]) Len() int {
+	return ring.count
+}
+
+//
+// Return the maximum number of values in the ring buffer.
+//
+func (ring *RingBuffer[T]) Capacity() int {
+	if len(ring.buffer) == 0 {
+		return 1
+	}
+
+	return len(ring.buffer)
+}
+
+//
+// Check if the ring buffer has no values.
+//
+func (ring *RingBuffer[T]) IsEmpty() bool {
+	return ring.count == 0
+}
+
+//
+// Check if the ring buffer is full, in which case the next
+// `Add` overwrites the oldest value.
+//
+func (ring *RingBuffer[T]) IsFull() bool {
+	return ring.count == ring.Capacity()
+}
+
+//
+// Add the value to the ring buffer. If the buffer is full the
+// oldest value is overwritten and returned along with `true`.
+//
+func (ring *RingBuffer[T]) Add(value T) (T, bool) {
+	if ring.buffer == nil {
+		ring.buffer = make([]T, 1)
+	}
+
+	if ring.count < len(ring.buffer) {
+		ring.buffer[(ring.head+ring.count)%len(ring.buffer)] = value
+		ring.count++
+
+		var zero T
+		return zero, false
+	}
+
+	evicted := ring.buffer[ring.head]
+	ring.buffer[ring.head] = value
+	ring.head = (ring.head + 1) % len(ring.buffer)
+	return evicted, true
+}
+
+//
+// Return the value at the given logical index, where `0` is
+// the oldest value. Returns `false` if the index is out of range.
+//
+func (ring *RingBuffer[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= ring.count {
+		var zero T
+		return zero, false
+	}
+
+	return ring.buffer[(ring.head+index)%len(ring.buffer)], true
+}
+
+//
+// Return the oldest value. Returns `false` if the ring
+// buffer is empty.
+//
+func (ring *RingBuffer[T]) Oldest() (T, bool) {
+	return ring.Get(0)
+}
+
+//
+// Return the most recently added value. Returns `false` if
+// the ring buffer is empty.
+//
+func (ring *RingBuffer[T]) Newest() (T, bool) {
+	return ring.Get(ring.count - 1)
+}
+
+//
+// Remove all values from the ring buffer.
+//
+func (ring *RingBuffer[T]) Clear() {
+	var zero T
+	for index := range ring.buffer {
+		ring.buffer[index] = zero
+	}
+
+	ring.head = 0
+	ring.count = 0
+}
+
+//
+// Return an iterator over the values, oldest first. The ring
+// buffer must not be modified while iterating.
+//
+func (ring *RingBuffer[T]) Iterator() Iterator[T] {
+	index := 0
+	return IteratorFunc[T](func() (T, bool) {
+		value, ok := ring.Get(index)
+		index++
+		return value, ok
+	})
+}
+
+//
+// Return a snapshot of the values, oldest first.
+//
+func (ring *RingBuffer[T]) ToSlice() []T {
+	result := make([]T, ring.count)
+	for index := 0; index < ring.count; index++ {
+		result[index] = ring.buffer[(ring.head+index)%len(ring.buffer)]
+	}
+
+	return result
+}
@@ -0,0 +1,93 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	ring := NewRingBuffer[int](3)
+	assert.True(t, ring.IsEmpty())
+	assert.False(t, ring.IsFull())
+	assert.Equal(t, 3, ring.Capacity())
+
+	_, ok := ring.Oldest()
+	assert.False(t, ok)
+	_, ok = ring.Newest()
+	assert.False(t, ok)
+
+	_, evicted := ring.Add(1)
+	assert.False(t, evicted)
+	ring.Add(2)
+	ring.Add(3)
+	assert.True(t, ring.IsFull())
+	assert.Equal(t, []int{1, 2, 3}, ring.ToSlice())
+
+	// overwrite oldest
+	value, evicted := ring.Add(4)
+	assert.True(t, evicted)
+	assert.Equal(t, 1, value)
+	value, evicted = ring.Add(5)
+	assert.True(t, evicted)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 3, ring.Len())
+	assert.Equal(t, []int{3, 4, 5}, ring.ToSlice())
+	assert.Equal(t, []int{3, 4, 5}, NewStream(ring.Iterator()).Collect())
+
+	value, _ = ring.Oldest()
+	assert.Equal(t, 3, value)
+	value, _ = ring.Newest()
+	assert.Equal(t, 5, value)
+
+	value, ok = ring.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 4, value)
+	_, ok = ring.Get(3)
+	assert.False(t, ok)
+
+	ring.Clear()
+	assert.True(t, ring.IsEmpty())
+	assert.Equal(t, []int{}, ring.ToSlice())
+
+	// minimum capacity
+	ring = NewRingBuffer[int](0)
+	ring.Add(1)
+	ring.Add(2)
+	assert.Equal(t, []int{2}, ring.ToSlice())
+}
+
+func TestRingBufferZeroValue(t *testing.T) {
+	var ring RingBuffer[string]
+	assert.True(t, ring.IsEmpty())
+	assert.False(t, ring.IsFull())
+	assert.Equal(t, 1, ring.Capacity())
+	assert.Equal(t, []string{}, ring.ToSlice())
+
+	_, ok := ring.Newest()
+	assert.False(t, ok)
+
+	_, evicted := ring.Add("a")
+	assert.False(t, evicted)
+	assert.True(t, ring.IsFull())
+
+	old, evicted := ring.Add("b")
+	assert.True(t, evicted)
+	assert.Equal(t, "a", old)
+	assert.Equal(t, []string{"b"}, ring.ToSlice())
+
+	ring.Clear()
+	assert.True(t, ring.IsEmpty())
+	assert.Equal(t, 1, ring.Capacity())
+}
@@ -0,0 +1,450 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+//
+// An iterator returns elements one at a time. `Next` returns
+// the next element and `true`, or the zero value and `false`
+// once the iterator is exhausted.
+//
+type Iterator[T any] interface {
+	Next() (T, bool)
+}
+
+//
+// Adapter to use an ordinary function as an `Iterator`.
+//
+type IteratorFunc[T any] func() (T, bool)
+
+//
+// Return the next element by calling the function.
+//
+func (fn IteratorFunc[T]) Next() (T, bool) {
+	return fn()
+}
+
+//
+// A single key-value pair of a map.
+//
+type MapEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+//
+// A lazy pipeline over an `Iterator`. Stages like `Filter` and
+// `Take` wrap the previous stage and pull elements only when the
+// next element is requested, so a pipeline over a huge input only
+// does the work it needs. A stream can be consumed only once.
+// Stages that change the element type, like `StreamMap`, are
+// functions as Go methods cannot declare type parameters.
+//
+type Stream[T any] struct {
+	iterator Iterator[T]
+	err      func() error
+}
+
+//
+// Create a new stream that reads from the given iterator. If
+// the iterator has an `Err() error` method it is reported by
+// the `Err` method of the stream.
+//
+func NewStream[T any](iterator Iterator[T]) *Stream[T] {
+	stream := &Stream[T]{
+		iterator: iterator,
+	}
+
+	if errorer, ok := iterator.(interface{ Err() error }); ok {
+		stream.err = errorer.Err
+	}
+
+	return stream
+}
+
+// create a new stage that inherits the error source of its parent
+func deriveStream[T any, R any](parent *Stream[T], iterator Iterator[R]) *Stream[R] {
+	return &Stream[R]{
+		iterator: iterator,
+		err:      parent.err,
+	}
+}
+
+//
+// Create a stream over the elements of the slice. Returns an
+// empty stream if the slice is `nil`.
+//
+func StreamFromSlice[T any](slice []T) *Stream[T] {
+	index := 0
+	return NewStream[T](IteratorFunc[T](func() (T, bool) {
+		if index >= len(slice) {
+			var zero T
+			return zero, false
+		}
+
+		item := slice[index]
+		index++
+		return item, true
+	}))
+}
+
+//
+// Create a stream over the entries of the map. The order of
+// entries is not defined. Returns an empty stream if the map
+// is `nil`.
+//
+func StreamFromMap[K comparable, V any](m map[K]V) *Stream[MapEntry[K, V]] {
+	iterator := reflect.ValueOf(m).MapRange()
+	return NewStream[MapEntry[K, V]](IteratorFunc[MapEntry[K, V]](func() (MapEntry[K, V], bool) {
+		if !iterator.Next() {
+			return MapEntry[K, V]{}, false
+		}
+
+		return MapEntry[K, V]{
+			Key:   iterator.Key().Interface().(K),
+			Value: iterator.Value().Interface().(V),
+		}, true
+	}))
+}
+
+//
+// Create a stream over the values received from the channel.
+// The stream ends when the channel is closed.
+//
+func StreamFromChannel[T any](channel <-chan T) *Stream[T] {
+	return NewStream[T](IteratorFunc[T](func() (T, bool) {
+		item, ok := <-channel
+		return item, ok
+	}))
+}
+
+//
+// Create a stream over the files in the given folder, in the
+// same order as `ListFiles`. In recursive mode a child folder is
+// read only when the walk reaches it. Any error reading a folder
+// ends the stream and is reported by `Err`.
+//
+func StreamFromFiles(path string, recursive bool) *Stream[*FileAsset] {
+	walker := &fileWalker{
+		recursive: recursive,
+	}
+
+	if path == "" {
+		walker.err = errors.New("Path for dir list cannot be empty")
+	} else {
+		walker.push(path)
+	}
+
+	return NewStream[*FileAsset](walker)
+}
+
+// a single folder being walked
+type fileWalkerFrame struct {
+	path     string
+	files    []os.FileInfo
+	position int
+}
+
+// lazily walks a folder tree depth first
+type fileWalker struct {
+	recursive bool
+	stack     []*fileWalkerFrame
+	err       error
+}
+
+// read the folder and push it on the stack
+func (walker *fileWalker) push(path string) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		walker.err = err
+		return
+	}
+
+	walker.stack = append(walker.stack, &fileWalkerFrame{path: path, files: files})
+}
+
+// return the next file from the walk
+func (walker *fileWalker) Next() (*FileAsset, bool) {
+	for walker.err == nil && len(walker.stack) > 0 {
+		frame := walker.stack[len(walker.stack)-1]
+		if frame.position >= len(frame.files) {
+			walker.stack = walker.stack[:len(walker.stack)-1]
+			continue
+		}
+
+		asset := newFileAsset(frame.path, frame.files[frame.position])
+		frame.position++
+
+		if walker.recursive && asset.IsFolder {
+			walker.push(filepath.Join(frame.path, asset.Name))
+			if walker.err != nil {
+				return nil, false
+			}
+		}
+
+		return &asset, true
+	}
+
+	return nil, false
+}
+
+// return the error encountered during the walk, if any
+func (walker *fileWalker) Err() error {
+	return walker.err
+}
+
+//
+// Return the next element of the stream.
+//
+func (stream *Stream[T]) Next() (T, bool) {
+	return stream.iterator.Next()
+}
+
+//
+// Return the error reported by the source of the stream, if
+// any. Sources that cannot fail always return `nil`.
+//
+func (stream *Stream[T]) Err() error {
+	if stream.err == nil {
+		return nil
+	}
+
+	return stream.err()
+}
+
+//
+// Return a stream with only the elements for which the
+// predicate returns `true`.
+//
+func (stream *Stream[T]) Filter(predicate func(item T) bool) *Stream[T] {
+	return deriveStream[T, T](stream, IteratorFunc[T](func() (T, bool) {
+		for {
+			item, ok := stream.iterator.Next()
+			if !ok || predicate(item) {
+				return item, ok
+			}
+		}
+	}))
+}
+
+//
+// Return a stream with at most the first `count` elements. The
+// source is not read once `count` elements have been returned.
+//
+func (stream *Stream[T]) Take(count int) *Stream[T] {
+	taken := 0
+	return deriveStream[T, T](stream, IteratorFunc[T](func() (T, bool) {
+		if taken >= count {
+			var zero T
+			return zero, false
+		}
+
+		taken++
+		return stream.iterator.Next()
+	}))
+}
+
+//
+// Return a stream that skips the first `count` elements.
+//
+func (stream *Stream[T]) Skip(count int) *Stream[T] {
+	skipped := false
+	return deriveStream[T, T](stream, IteratorFunc[T](func() (T, bool) {
+		if !skipped {
+			skipped = true
+			for index := 0; index < count; index++ {
+				if _, ok := stream.iterator.Next(); !ok {
+					var zero T
+					return zero, false
+				}
+			}
+		}
+
+		return stream.iterator.Next()
+	}))
+}
+
+//
+// Return a stream of the elements as long as the predicate
+// returns `true`. The stream ends at the first element for
+// which the predicate returns `false`.
+//
+func (stream *Stream[T]) TakeWhile(predicate func(item T) bool) *Stream[T] {
+	done := false
+	return deriveStream[T, T](stream, IteratorFunc[T](func() (T, bool) {
+		var zero T
+		if done {
+			return zero, false
+		}
+
+		item, ok := stream.iterator.Next()
+		if !ok || !predicate(item) {
+			done = true
+			return zero, false
+		}
+
+		return item, true
+	}))
+}
+
+//
+// Call the consumer for each remaining element of the stream.
+//
+func (stream *Stream[T]) ForEach(consumer func(item T)) {
+	for {
+		item, ok := stream.iterator.Next()
+		if !ok {
+			return
+		}
+
+		consumer(item)
+	}
+}
+
+//
+// Read all remaining elements of the stream into a slice.
+//
+func (stream *Stream[T]) Collect() []T {
+	result := make([]T, 0)
+	stream.ForEach(func(item T) {
+		result = append(result, item)
+	})
+
+	return result
+}
+
+//
+// Return the number of remaining elements in the stream.
+//
+func (stream *Stream[T]) Count() int {
+	count := 0
+	stream.ForEach(func(item T) {
+		count++
+	})
+
+	return count
+}
+
+//
+// Return the first element of the stream, and `false` if
+// the stream is empty.
+//
+func (stream *Stream[T]) First() (T, bool) {
+	return stream.iterator.Next()
+}
+
+//
+// Return a stream of the results of calling the mapper on
+// each element of the stream.
+//
+func StreamMap[T any, R any](stream *Stream[T], mapper func(item T) R) *Stream[R] {
+	return deriveStream[T, R](stream, IteratorFunc[R](func() (R, bool) {
+		item, ok := stream.iterator.Next()
+		if !ok {
+			var zero R
+			return zero, false
+		}
+
+		return mapper(item), true
+	}))
+}
+
+//
+// Return a stream of all the elements of the iterators returned
+// by calling the mapper on each element of the stream. Use
+// `StreamFromSlice` to return a slice from the mapper.
+//
+func StreamFlatMap[T any, R any](stream *Stream[T], mapper func(item T) Iterator[R]) *Stream[R] {
+	var current Iterator[R]
+	return deriveStream[T, R](stream, IteratorFunc[R](func() (R, bool) {
+		for {
+			if current != nil {
+				if item, ok := current.Next(); ok {
+					return item, true
+				}
+				current = nil
+			}
+
+			item, ok := stream.iterator.Next()
+			if !ok {
+				var zero R
+				return zero, false
+			}
+
+			current = mapper(item)
+		}
+	}))
+}
+
+//
+// Return a stream that skips elements already returned before.
+//
+func StreamDistinct[T comparable](stream *Stream[T]) *Stream[T] {
+	seen := make(map[T]struct{})
+	return stream.Filter(func(item T) bool {
+		if _, found := seen[item]; found {
+			return false
+		}
+
+		seen[item] = struct{}{}
+		return true
+	})
+}
+
+//
+// Return a stream of slices of `size` consecutive elements. The
+// last slice may contain fewer elements. A `size` of less than
+// one is treated as one.
+//
+func StreamChunk[T any](stream *Stream[T], size int) *Stream[[]T] {
+	if size < 1 {
+		size = 1
+	}
+
+	return deriveStream[T, []T](stream, IteratorFunc[[]T](func() ([]T, bool) {
+		chunk := make([]T, 0, size)
+		for len(chunk) < size {
+			item, ok := stream.iterator.Next()
+			if !ok {
+				break
+			}
+
+			chunk = append(chunk, item)
+		}
+
+		if len(chunk) == 0 {
+			return nil, false
+		}
+
+		return chunk, true
+	}))
+}
+
+//
+// Reduce the stream to a single value by calling the reducer with
+// the accumulated value and each element, starting from `initial`.
+//
+func StreamReduce[T any, R any](stream *Stream[T], initial R, reducer func(accumulated R, item T) R) R {
+	result := initial
+	stream.ForEach(func(item T) {
+		result = reducer(result, item)
+	})
+
+	return result
+}
@@ -0,0 +1,148 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// returns an endless stream of natural numbers, counting the reads
+func naturalNumbers(reads *int) *Stream[int] {
+	current := 0
+	return NewStream[int](IteratorFunc[int](func() (int, bool) {
+		*reads++
+		current++
+		return current, true
+	}))
+}
+
+func TestStreamFromSlice(t *testing.T) {
+	var foo []int
+	assert.Equal(t, []int{}, StreamFromSlice(foo).Collect())
+
+	foo = []int{1, 2, 3}
+	assert.Equal(t, []int{1, 2, 3}, StreamFromSlice(foo).Collect())
+	assert.Equal(t, 3, StreamFromSlice(foo).Count())
+	assert.NoError(t, StreamFromSlice(foo).Err())
+
+	first, ok := StreamFromSlice(foo).First()
+	assert.True(t, ok)
+	assert.Equal(t, 1, first)
+
+	_, ok = StreamFromSlice(foo).Skip(5).First()
+	assert.False(t, ok)
+}
+
+func TestStreamFromMap(t *testing.T) {
+	var foo map[string]int
+	assert.Equal(t, 0, StreamFromMap(foo).Count())
+
+	foo = map[string]int{"a": 1, "b": 2}
+	entries := StreamFromMap(foo).Collect()
+	assert.ElementsMatch(t, []MapEntry[string, int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}}, entries)
+}
+
+func TestStreamFromChannel(t *testing.T) {
+	channel := make(chan int, 3)
+	channel <- 1
+	channel <- 2
+	channel <- 3
+	close(channel)
+
+	assert.Equal(t, []int{1, 2, 3}, StreamFromChannel(channel).Collect())
+}
+
+func TestStreamStages(t *testing.T) {
+	reads := 0
+	even := func(x int) bool {
+		return x%2 == 0
+	}
+
+	// short circuits an endless source
+	result := naturalNumbers(&reads).Filter(even).Skip(1).Take(3).Collect()
+	assert.Equal(t, []int{4, 6, 8}, result)
+	assert.Equal(t, 8, reads)
+
+	reads = 0
+	result = naturalNumbers(&reads).TakeWhile(func(x int) bool { return x < 4 }).Collect()
+	assert.Equal(t, []int{1, 2, 3}, result)
+	assert.Equal(t, 4, reads)
+
+	strings := StreamMap(StreamFromSlice([]int{1, 2}), func(x int) string {
+		return strconv.Itoa(x * 10)
+	}).Collect()
+	assert.Equal(t, []string{"10", "20"}, strings)
+
+	flat := StreamFlatMap(StreamFromSlice([]int{1, 0, 2}), func(x int) Iterator[int] {
+		items := make([]int, x)
+		for index := range items {
+			items[index] = x
+		}
+		return StreamFromSlice(items)
+	}).Collect()
+	assert.Equal(t, []int{1, 2, 2}, flat)
+
+	distinct := StreamDistinct(StreamFromSlice([]int{1, 2, 1, 3, 2})).Collect()
+	assert.Equal(t, []int{1, 2, 3}, distinct)
+
+	chunks := StreamChunk(StreamFromSlice([]int{1, 2, 3, 4, 5}), 2).Collect()
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+
+	reads = 0
+	sum := StreamReduce(naturalNumbers(&reads).Take(4), 0, func(total int, x int) int {
+		return total + x
+	})
+	assert.Equal(t, 10, sum)
+	assert.Equal(t, 4, reads)
+}
+
+func TestStreamFromFiles(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "a"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "a", "x.txt"), []byte("x"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "b.txt"), []byte("b"), 0644))
+
+	names := func(assets []*FileAsset) []string {
+		result := make([]string, len(assets))
+		for index, asset := range assets {
+			result[index] = asset.Name
+		}
+		return result
+	}
+
+	// same order as ListFiles
+	expected, err := ListFiles(root, true)
+	assert.NoError(t, err)
+
+	stream := StreamFromFiles(root, true)
+	assert.Equal(t, names(expected), names(stream.Collect()))
+	assert.NoError(t, stream.Err())
+	assert.Equal(t, []string{"a", "x.txt", "b.txt"}, names(expected))
+
+	stream = StreamFromFiles(root, false)
+	assert.Equal(t, []string{"a", "b.txt"}, names(stream.Collect()))
+
+	// errors are reported through all stages
+	stream = StreamFromFiles(filepath.Join(root, "missing"), true)
+	mapped := StreamMap(stream, func(asset *FileAsset) string {
+		return asset.Name
+	})
+	assert.Equal(t, 0, mapped.Count())
+	assert.Error(t, mapped.Err())
+
+	assert.Error(t, StreamFromFiles("", true).Err())
+}
@@ -0,0 +1,536 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+//
+// A map that is safe for concurrent use, guarded by a
+// read-write mutex. Callbacks passed to `ComputeIfAbsent`,
+// `Update` and `Range` run while the lock is held and must
+// not call back into the map. The zero value is an empty map
+// ready to use.
+//
+type SyncMap[K comparable, V any] struct {
+	mutex   sync.RWMutex
+	entries map[K]V
+}
+
+//
+// Create a new empty concurrent map.
+//
+func NewSyncMap[K comparable, V any]() *SyncMap[K, V] {
+	return &SyncMap[K, V]{
+		entries: make(map[K]V),
+	}
+}
+
+//
+// Return the value for the key, and `false` if the key is
+// not present.
+//
+func (sm *SyncMap[K, V]) Get(key K) (V, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	value, found := sm.entries[key]
+	return value, found
+}
+
+//
+// Set the value for the key.
+//
+func (sm *SyncMap[K, V]) Set(key K, value V) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.entries == nil {
+		sm.entries = make(map[K]V)
+	}
+	sm.entries[key] = value
+}
+
+//
+// Set the value for the key only if the key is not present.
+// Returns the value now in the map, and `true` if it was
+// already present.
+//
+func (sm *SyncMap[K, V]) SetIfAbsent(key K, value V) (V, bool) {
+	return sm.ComputeIfAbsent(key, func(key K) V {
+		return value
+	})
+}
+
+//
+// Return the value for the key. If the key is not present the
+// value is computed by calling `compute`, stored and returned.
+// The function is called at most once per missing key, even when
+// many goroutines ask for it at the same time. Returns `true` if
+// the value was already present.
+//
+func (sm *SyncMap[K, V]) ComputeIfAbsent(key K, compute func(key K) V) (V, bool) {
+	sm.mutex.RLock()
+	value, found := sm.entries[key]
+	sm.mutex.RUnlock()
+	if found {
+		return value, true
+	}
+
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	// check again as another goroutine may have added it
+	if value, found = sm.entries[key]; found {
+		return value, true
+	}
+
+	if sm.entries == nil {
+		sm.entries = make(map[K]V)
+	}
+
+	value = compute(key)
+	sm.entries[key] = value
+	return value, false
+}
+
+//
+// Atomically update the value for the key. The updater receives
+// the current value and whether it is present, and returns the
+// new value and whether to keep it; returning `false` deletes the
+// key. Returns the new value and whether the key is present.
+//
+func (sm *SyncMap[K, V]) Update(key K, updater func(value V, found bool) (V, bool)) (V, bool) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.entries == nil {
+		sm.entries = make(map[K]V)
+	}
+
+	current, found := sm.entries[key]
+	updated, keep := updater(current, found)
+	if !keep {
+		delete(sm.entries, key)
+
+		var zero V
+		return zero, false
+	}
+
+	sm.entries[key] = updated
+	return updated, true
+}
+
+//
+// Remove the key from the map and return its value. Returns
+// `false` if the key was not present.
+//
+func (sm *SyncMap[K, V]) Delete(key K) (V, bool) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	value, found := sm.entries[key]
+	if found {
+		delete(sm.entries, key)
+	}
+
+	return value, found
+}
+
+//
+// Return the number of entries in the map.
+//
+func (sm *SyncMap[K, V]) Len() int {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return len(sm.entries)
+}
+
+//
+// Return the keys in the map in no particular order.
+//
+func (sm *SyncMap[K, V]) Keys() []K {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return MapKeys(sm.entries)
+}
+
+//
+// Call the consumer for each entry, in no particular order,
+// stopping when it returns `false`.
+//
+func (sm *SyncMap[K, V]) Range(consumer func(key K, value V) bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	for key, value := range sm.entries {
+		if !consumer(key, value) {
+			return
+		}
+	}
+}
+
+//
+// Return a copy of the entries as a plain Go map.
+//
+func (sm *SyncMap[K, V]) ToMap() map[K]V {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	result := make(map[K]V, len(sm.entries))
+	for key, value := range sm.entries {
+		result[key] = value
+	}
+
+	return result
+}
+
+//
+// Remove all entries from the map.
+//
+func (sm *SyncMap[K, V]) Clear() {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.entries = make(map[K]V)
+}
+
+//
+// A set that is safe for concurrent use. The zero value is an
+// empty set ready to use.
+//
+type SyncSet[T comparable] struct {
+	entries SyncMap[T, struct{}]
+}
+
+//
+// Create a new concurrent set with the given values.
+//
+func NewSyncSet[T comparable](values ...T) *SyncSet[T] {
+	set := &SyncSet[T]{}
+	for _, value := range values {
+		set.Add(value)
+	}
+
+	return set
+}
+
+//
+// Add the value to the set. Returns `true` if the value was
+// not already present.
+//
+func (set *SyncSet[T]) Add(value T) bool {
+	_, found := set.entries.SetIfAbsent(value, struct{}{})
+	return !found
+}
+
+//
+// Remove the value from the set. Returns `true` if the value
+// was present.
+//
+func (set *SyncSet[T]) Remove(value T) bool {
+	_, found := set.entries.Delete(value)
+	return found
+}
+
+//
+// Check if the value is present in the set.
+//
+func (set *SyncSet[T]) Contains(value T) bool {
+	_, found := set.entries.Get(value)
+	return found
+}
+
+//
+// Return the number of values in the set.
+//
+func (set *SyncSet[T]) Len() int {
+	return set.entries.Len()
+}
+
+//
+// Return the values in the set in no particular order.
+//
+func (set *SyncSet[T]) Values() []T {
+	return set.entries.Keys()
+}
+
+//
+// Remove all values from the set.
+//
+func (set *SyncSet[T]) Clear() {
+	set.entries.Clear()
+}
+
+//
+// A concurrent map split into shards, each guarded by its own
+// lock, so that goroutines working on different keys rarely
+// contend. Keys are hashed from their value, so that keys equal
+// as per `==`, like `0.0` and `-0.0`, always share a shard.
+//
+type ShardedMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*SyncMap[K, V]
+}
+
+//
+// Create a new sharded map with the given number of shards.
+// A `shards` value of less than one uses `16` shards.
+//
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	if shards < 1 {
+		shards = 16
+	}
+
+	sm := &ShardedMap[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*SyncMap[K, V], shards),
+	}
+
+	for index := range sm.shards {
+		sm.shards[index] = NewSyncMap[K, V]()
+	}
+
+	return sm
+}
+
+// return the shard that holds the key
+func (sm *ShardedMap[K, V]) shard(key K) *SyncMap[K, V] {
+	var hash maphash.Hash
+	hash.SetSeed(sm.seed)
+	if !hashKey(&hash, key) {
+		hashValue(&hash, reflect.ValueOf(key))
+	}
+
+	return sm.shards[hash.Sum64()%uint64(len(sm.shards))]
+}
+
+// write the string or number to the hash without allocating,
+// returning `false` for keys of other types
+func hashKey(hash *maphash.Hash, key interface{}) bool {
+	switch k := key.(type) {
+	case string:
+		hash.WriteString(k)
+
+	case int:
+		hashBits(hash, uint64(k))
+
+	case int8:
+		hashBits(hash, uint64(k))
+
+	case int16:
+		hashBits(hash, uint64(k))
+
+	case int32:
+		hashBits(hash, uint64(k))
+
+	case int64:
+		hashBits(hash, uint64(k))
+
+	case uint:
+		hashBits(hash, uint64(k))
+
+	case uint8:
+		hashBits(hash, uint64(k))
+
+	case uint16:
+		hashBits(hash, uint64(k))
+
+	case uint32:
+		hashBits(hash, uint64(k))
+
+	case uint64:
+		hashBits(hash, k)
+
+	case uintptr:
+		hashBits(hash, uint64(k))
+
+	case float32:
+		hashFloat(hash, float64(k))
+
+	case float64:
+		hashFloat(hash, k)
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// write the value of any comparable kind to the hash, so that
+// values equal as per `==` write the same bytes, field by field
+// and element by element for structs and arrays
+func hashValue(hash *maphash.Hash, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			hash.WriteByte(1)
+		} else {
+			hash.WriteByte(0)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		hashBits(hash, uint64(value.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		hashBits(hash, value.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		hashFloat(hash, value.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		number := value.Complex()
+		hashFloat(hash, real(number))
+		hashFloat(hash, imag(number))
+
+	case reflect.String:
+		hash.WriteString(value.String())
+
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		hashBits(hash, uint64(value.Pointer()))
+
+	case reflect.Interface:
+		hashValue(hash, value.Elem())
+
+	case reflect.Array:
+		for index := 0; index < value.Len(); index++ {
+			hashValue(hash, value.Index(index))
+		}
+
+	case reflect.Struct:
+		for index := 0; index < value.NumField(); index++ {
+			hashValue(hash, value.Field(index))
+		}
+
+	default:
+		// a nil interface
+		hash.WriteByte(0)
+	}
+}
+
+// write the bits of the number to the hash
+func hashBits(hash *maphash.Hash, bits uint64) {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], bits)
+	hash.Write(buffer[:])
+}
+
+// write the float to the hash, with `-0` and `+0` being equal
+func hashFloat(hash *maphash.Hash, number float64) {
+	if number == 0 {
+		number = 0
+	}
+
+	hashBits(hash, math.Float64bits(number))
+}
+
+//
+// See `SyncMap.Get`.
+//
+func (sm *ShardedMap[K, V]) Get(key K) (V, bool) {
+	return sm.shard(key).Get(key)
+}
+
+//
+// See `SyncMap.Set`.
+//
+func (sm *ShardedMap[K, V]) Set(key K, value V) {
+	sm.shard(key).Set(key, value)
+}
+
+//
+// See `SyncMap.SetIfAbsent`.
+//
+func (sm *ShardedMap[K, V]) SetIfAbsent(key K, value V) (V, bool) {
+	return sm.shard(key).SetIfAbsent(key, value)
+}
+
+//
+// See `SyncMap.ComputeIfAbsent`.
+//
+func (sm *ShardedMap[K, V]) ComputeIfAbsent(key K, compute func(key K) V) (V, bool) {
+	return sm.shard(key).ComputeIfAbsent(key, compute)
+}
+
+//
+// See `SyncMap.Update`.
+//
+func (sm *ShardedMap[K, V]) Update(key K, updater func(value V, found bool) (V, bool)) (V, bool) {
+	return sm.shard(key).Update(key, updater)
+}
+
+//
+// See `SyncMap.Delete`.
+//
+func (sm *ShardedMap[K, V]) Delete(key K) (V, bool) {
+	return sm.shard(key).Delete(key)
+}
+
+//
+// Return the number of entries in the map. The count is not
+// a consistent snapshot if the map is modified concurrently.
+//
+func (sm *ShardedMap[K, V]) Len() int {
+	length := 0
+	for _, shard := range sm.shards {
+		length += shard.Len()
+	}
+
+	return length
+}
+
+//
+// Return the keys in the map in no particular order.
+//
+func (sm *ShardedMap[K, V]) Keys() []K {
+	keys := make([]K, 0)
+	for _, shard := range sm.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+
+	return keys
+}
+
+//
+// Call the consumer for each entry, one shard at a time,
+// stopping when it returns `false`.
+//
+func (sm *ShardedMap[K, V]) Range(consumer func(key K, value V) bool) {
+	proceed := true
+	for _, shard := range sm.shards {
+		shard.Range(func(key K, value V) bool {
+			proceed = consumer(key, value)
+			return proceed
+		})
+
+		if !proceed {
+			return
+		}
+	}
+}
+
+//
+// Remove all entries from the map.
+//
+func (sm *ShardedMap[K, V]) Clear() {
+	for _, shard := range sm.shards {
+		shard.Clear()
+	}
+}
@@ -0,0 +1,322 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMap(t *testing.T) {
+	var sm SyncMap[string, int]
+	_, found := sm.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, 0, sm.Len())
+
+	sm.Set("a", 1)
+	value, found := sm.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	value, found = sm.SetIfAbsent("a", 10)
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+	value, found = sm.SetIfAbsent("b", 2)
+	assert.False(t, found)
+	assert.Equal(t, 2, value)
+
+	// update
+	value, found = sm.Update("a", func(value int, found bool) (int, bool) {
+		return value + 5, true
+	})
+	assert.True(t, found)
+	assert.Equal(t, 6, value)
+
+	_, found = sm.Update("b", func(value int, found bool) (int, bool) {
+		return 0, false
+	})
+	assert.False(t, found)
+	assert.Equal(t, []string{"a"}, sm.Keys())
+
+	value, found = sm.Delete("a")
+	assert.True(t, found)
+	assert.Equal(t, 6, value)
+	_, found = sm.Delete("a")
+	assert.False(t, found)
+
+	sm.Set("x", 1)
+	sm.Set("y", 2)
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, sm.ToMap())
+
+	visited := 0
+	sm.Range(func(key string, value int) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 1, visited)
+
+	sm.Clear()
+	assert.Equal(t, 0, sm.Len())
+
+	// the zero value can be updated
+	var other SyncMap[string, int]
+	other.Update("a", func(value int, found bool) (int, bool) {
+		assert.False(t, found)
+		return 1, true
+	})
+	assert.Equal(t, 1, other.Len())
+}
+
+func TestSyncMapComputeIfAbsent(t *testing.T) {
+	sm := NewSyncMap[int, int]()
+
+	var calls int32
+	var wg sync.WaitGroup
+	for worker := 0; worker < 16; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := 0; key < 100; key++ {
+				value, _ := sm.ComputeIfAbsent(key, func(key int) int {
+					atomic.AddInt32(&calls, 1)
+					return key * key
+				})
+				assert.Equal(t, key*key, value)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(100), calls)
+	assert.Equal(t, 100, sm.Len())
+}
+
+func TestSyncMapConcurrentUpdate(t *testing.T) {
+	sm := NewSyncMap[string, int]()
+	sharded := NewShardedMap[string, int](4)
+
+	increment := func(value int, found bool) (int, bool) {
+		return value + 1, true
+	}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := 0; index < 1000; index++ {
+				key := strconv.Itoa(index % 10)
+				sm.Update(key, increment)
+				sharded.Update(key, increment)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for key := 0; key < 10; key++ {
+		value, _ := sm.Get(strconv.Itoa(key))
+		assert.Equal(t, 800, value)
+		value, _ = sharded.Get(strconv.Itoa(key))
+		assert.Equal(t, 800, value)
+	}
+}
+
+func TestSyncSet(t *testing.T) {
+	set := NewSyncSet(1, 2, 2, 3)
+	assert.Equal(t, 3, set.Len())
+	assert.True(t, set.Contains(2))
+	assert.False(t, set.Contains(4))
+
+	assert.True(t, set.Add(4))
+	assert.False(t, set.Add(4))
+	assert.True(t, set.Remove(1))
+	assert.False(t, set.Remove(1))
+	assert.ElementsMatch(t, []int{2, 3, 4}, set.Values())
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for index := 0; index < 100; index++ {
+				set.Add(worker*100 + index)
+				set.Contains(index)
+			}
+		}(worker)
+	}
+	wg.Wait()
+	assert.Equal(t, 800, set.Len())
+
+	set.Clear()
+	assert.Equal(t, 0, set.Len())
+}
+
+func TestShardedMap(t *testing.T) {
+	sm := NewShardedMap[int, string](0)
+	assert.Equal(t, 16, len(sm.shards))
+
+	for index := 0; index < 100; index++ {
+		sm.Set(index, strconv.Itoa(index))
+	}
+	assert.Equal(t, 100, sm.Len())
+	assert.Equal(t, 100, len(sm.Keys()))
+
+	// keys are spread over shards
+	used := 0
+	for _, shard := range sm.shards {
+		if shard.Len() > 0 {
+			used++
+		}
+	}
+	assert.Greater(t, used, 1)
+
+	value, found := sm.Get(42)
+	assert.True(t, found)
+	assert.Equal(t, "42", value)
+
+	value, found = sm.SetIfAbsent(42, "x")
+	assert.True(t, found)
+	assert.Equal(t, "42", value)
+
+	value, found = sm.ComputeIfAbsent(100, func(key int) string {
+		return "hundred"
+	})
+	assert.False(t, found)
+	assert.Equal(t, "hundred", value)
+
+	_, found = sm.Delete(100)
+	assert.True(t, found)
+
+	visited := 0
+	sm.Range(func(key int, value string) bool {
+		visited++
+		return visited < 5
+	})
+	assert.Equal(t, 5, visited)
+
+	sm.Clear()
+	assert.Equal(t, 0, sm.Len())
+
+	// other key types
+	structs := NewShardedMap[struct{ a, b int }, int](4)
+	structs.Set(struct{ a, b int }{1, 2}, 3)
+	value2, found := structs.Get(struct{ a, b int }{1, 2})
+	assert.True(t, found)
+	assert.Equal(t, 3, value2)
+
+	// equal keys share a shard
+	floats := NewShardedMap[float64, int](16)
+	negativeZero := math.Copysign(0, -1)
+	floats.Set(0.0, 1)
+	floats.Set(negativeZero, 2)
+	assert.Equal(t, 1, floats.Len())
+	floatValue, found := floats.Get(0.0)
+	assert.True(t, found)
+	assert.Equal(t, 2, floatValue)
+
+	type point struct {
+		x     float32
+		label string
+	}
+	points := NewShardedMap[point, int](16)
+	for index := 0; index < 20; index++ {
+		points.Set(point{x: float32(negativeZero), label: strconv.Itoa(index)}, index)
+		points.Set(point{x: 0, label: strconv.Itoa(index)}, index)
+	}
+	assert.Equal(t, 20, points.Len())
+
+	type mixed struct {
+		pair    [2]complex64
+		pointer *point
+		flag    bool
+	}
+	shared := &point{}
+	mixes := NewShardedMap[mixed, int](16)
+	mixes.Set(mixed{pair: [2]complex64{complex64(complex(negativeZero, 1))}, pointer: shared}, 1)
+	mixes.Set(mixed{pair: [2]complex64{complex64(complex(0, 1))}, pointer: shared}, 2)
+	mixes.Set(mixed{pointer: &point{}}, 3)
+	mixes.Set(mixed{flag: true}, 4)
+	assert.Equal(t, 3, mixes.Len())
+	value3, found := mixes.Get(mixed{pair: [2]complex64{complex64(complex(0, 1))}, pointer: shared})
+	assert.True(t, found)
+	assert.Equal(t, 2, value3)
+}
+
+// the number of keys used by the benchmarks
+const benchmarkKeys = 1024
+
+// mixed workload of 90% reads and 10% writes
+func runMapBenchmark(b *testing.B, get func(key int), set func(key int)) {
+	for key := 0; key < benchmarkKeys; key++ {
+		set(key)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		index := 0
+		for pb.Next() {
+			key := index % benchmarkKeys
+			if index%10 == 0 {
+				set(key)
+			} else {
+				get(key)
+			}
+			index++
+		}
+	})
+}
+
+func BenchmarkSyncMap(b *testing.B) {
+	sm := NewSyncMap[int, int]()
+	runMapBenchmark(b, func(key int) {
+		sm.Get(key)
+	}, func(key int) {
+		sm.Set(key, key)
+	})
+}
+
+func BenchmarkShardedMap(b *testing.B) {
+	sm := NewShardedMap[int, int](32)
+	runMapBenchmark(b, func(key int) {
+		sm.Get(key)
+	}, func(key int) {
+		sm.Set(key, key)
+	})
+}
+
+func BenchmarkStdSyncMap(b *testing.B) {
+	var sm sync.Map
+	runMapBenchmark(b, func(key int) {
+		sm.Load(key)
+	}, func(key int) {
+		sm.Store(key, key)
+	})
+}
+
+func BenchmarkMutexMap(b *testing.B) {
+	var mutex sync.Mutex
+	m := make(map[int]int)
+	runMapBenchmark(b, func(key int) {
+		mutex.Lock()
+		_ = m[key]
+		mutex.Unlock()
+	}, func(key int) {
+		mutex.Lock()
+		m[key] = key
+		mutex.Unlock()
+	})
+}
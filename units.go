@@ -0,0 +1,354 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// Defines the family of units used to format byte sizes.
+//
+type ByteUnits int
+
+const (
+	// decimal units that are powers of 1000, like `kB` and `MB`
+	ByteUnitsSI ByteUnits = iota
+
+	// binary units that are powers of 1024, like `KiB` and `MiB`
+	ByteUnitsIEC
+)
+
+// the unit names for each power, starting at bytes
+var siByteUnits = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+var iecByteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// the multiplier for each size unit, in lower case
+var byteUnitSizes = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"e":   1e18,
+	"eb":  1e18,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+	"ei":  1 << 60,
+	"eib": 1 << 60,
+}
+
+//
+// Parse a human readable size such as `200MB`, `1.5 GiB` or
+// `512` into a number of bytes. Both SI units that are powers
+// of 1000 (`kB`, `MB`, ...) and IEC units that are powers of
+// 1024 (`KiB`, `MiB`, ...) are accepted, ignoring case, and
+// the trailing `B` may be left out. Fractional sizes are rounded
+// to the nearest byte. Returns a `*NumberRangeError` for negative
+// sizes or sizes that do not fit in `uint64`.
+//
+func ParseBytes(text string) (uint64, error) {
+	syntaxError := &strconv.NumError{Func: "ParseBytes", Num: text, Err: strconv.ErrSyntax}
+
+	trimmed := strings.TrimSpace(text)
+	split := strings.LastIndexFunc(trimmed, func(r rune) bool {
+		return r >= '0' && r <= '9' || r == '.'
+	}) + 1
+	if split == 0 {
+		return 0, syntaxError
+	}
+
+	multiplier, found := byteUnitSizes[strings.ToLower(strings.TrimSpace(trimmed[split:]))]
+	if !found {
+		return 0, syntaxError
+	}
+
+	number, err := ParseNumber(trimmed[:split], &NumberFormat{Underscores: true, ThousandsSeparator: ','})
+	if err != nil {
+		return 0, syntaxError
+	}
+
+	switch n := number.(type) {
+	case int64:
+		if n < 0 {
+			return 0, &NumberRangeError{Value: text, Target: "bytes", Reason: NumberNegative}
+		}
+		return multiplyBytes(text, uint64(n), multiplier)
+
+	case uint64:
+		return multiplyBytes(text, n, multiplier)
+	}
+
+	size := number.(float64)
+	if size < 0 {
+		return 0, &NumberRangeError{Value: text, Target: "bytes", Reason: NumberNegative}
+	}
+
+	size = math.Round(size * float64(multiplier))
+	if size >= math.Ldexp(1, 64) {
+		return 0, &NumberRangeError{Value: text, Target: "bytes", Reason: NumberOverflow}
+	}
+
+	return uint64(size), nil
+}
+
+// multiply the count by the unit size, checking for overflow
+func multiplyBytes(text string, count uint64, multiplier uint64) (uint64, error) {
+	high, low := bits.Mul64(count, multiplier)
+	if high != 0 {
+		return 0, &NumberRangeError{Value: text, Target: "bytes", Reason: NumberOverflow}
+	}
+
+	return low, nil
+}
+
+//
+// Format the number of bytes in the largest unit of the family
+// that keeps the value at least 1, like `1.50 GiB` or `200 MB`.
+// The value is written with `precision` digits after the point;
+// sizes under one kilobyte are written as whole bytes.
+//
+func FormatBytes(bytes uint64, units ByteUnits, precision int) string {
+	names, base := siByteUnits, 1000.0
+	if units == ByteUnitsIEC {
+		names, base = iecByteUnits, 1024.0
+	}
+
+	if bytes < uint64(base) {
+		return strconv.FormatUint(bytes, 10) + " B"
+	}
+
+	value := float64(bytes)
+	unit := 0
+	for value >= base && unit < len(names)-1 {
+		value /= base
+		unit++
+	}
+
+	// rounding may carry into the next unit, like 999.99 kB
+	formatted := strconv.FormatFloat(value, 'f', precision, 64)
+	if rounded, _ := strconv.ParseFloat(formatted, 64); rounded >= base && unit < len(names)-1 {
+		formatted = strconv.FormatFloat(value/base, 'f', precision, 64)
+		unit++
+	}
+
+	return formatted + " " + names[unit]
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// number of bytes. Strings are parsed with `ParseBytes`, so
+// that `"1.5GiB"` and `"200MB"` are accepted; other values are
+// converted as by `ConvertToUint64WithOptions`. Returns the
+// `defaultValue` if the value is `nil` or not supported.
+//
+func ConvertToBytes(value interface{}, defaultValue uint64) (uint64, error) {
+	return ConvertToBytesWithOptions(value, defaultValue, nil)
+}
+
+//
+// Convert the given value represented as `interface{}` to a
+// number of bytes as `ConvertToBytes` does, converting values
+// other than strings with the given options.
+//
+func ConvertToBytesWithOptions(value interface{}, defaultValue uint64, options *ConvertOptions) (uint64, error) {
+	if text, ok := primitiveOf(value).(string); ok {
+		size, err := ParseBytes(text)
+		if err != nil {
+			return defaultValue, err
+		}
+
+		return size, nil
+	}
+
+	return ConvertToUint64WithOptions(value, defaultValue, options)
+}
+
+// the duration for each unit accepted by `ParseDuration`
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"μs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+//
+// Parse a duration such as `90s`, `1.5h` or `3d4h`. Accepts the
+// units of `time.ParseDuration` plus `d` for days of 24 hours
+// and `w` for weeks of 7 days, and surrounding whitespace.
+// Returns a `*NumberRangeError` if the duration does not fit
+// in `time.Duration`.
+//
+func ParseDuration(text string) (time.Duration, error) {
+	syntaxError := &strconv.NumError{Func: "ParseDuration", Num: text, Err: strconv.ErrSyntax}
+
+	remaining := strings.TrimSpace(text)
+	negative := false
+	if strings.HasPrefix(remaining, "-") || strings.HasPrefix(remaining, "+") {
+		negative = remaining[0] == '-'
+		remaining = remaining[1:]
+	}
+
+	if remaining == "0" {
+		return 0, nil
+	}
+
+	if remaining == "" {
+		return 0, syntaxError
+	}
+
+	var total uint64
+	for remaining != "" {
+		// the number
+		end := strings.IndexFunc(remaining, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if end <= 0 {
+			return 0, syntaxError
+		}
+		number := remaining[:end]
+		remaining = remaining[end:]
+
+		// the unit
+		end = strings.IndexFunc(remaining, func(r rune) bool {
+			return r == '.' || (r >= '0' && r <= '9')
+		})
+		if end < 0 {
+			end = len(remaining)
+		}
+		unit, found := durationUnits[remaining[:end]]
+		if !found {
+			return 0, syntaxError
+		}
+		remaining = remaining[end:]
+
+		whole, fraction := number, ""
+		if index := strings.IndexByte(number, '.'); index >= 0 {
+			whole, fraction = number[:index], number[index+1:]
+			if strings.Contains(fraction, ".") || (whole == "" && fraction == "") {
+				return 0, syntaxError
+			}
+		}
+
+		var amount uint64
+		if whole != "" {
+			parsed, err := strconv.ParseUint(whole, 10, 64)
+			if err != nil {
+				return 0, &NumberRangeError{Value: text, Target: "time.Duration", Reason: NumberOverflow}
+			}
+
+			high, low := bits.Mul64(parsed, uint64(unit))
+			if high != 0 {
+				return 0, &NumberRangeError{Value: text, Target: "time.Duration", Reason: NumberOverflow}
+			}
+			amount = low
+		}
+
+		if fraction != "" {
+			parsed, _ := strconv.ParseFloat("0."+fraction, 64)
+			amount += uint64(math.Round(parsed * float64(unit)))
+		}
+
+		total += amount
+		if total < amount || total > 1<<63 {
+			return 0, &NumberRangeError{Value: text, Target: "time.Duration", Reason: NumberOverflow}
+		}
+	}
+
+	if negative {
+		return -time.Duration(total), nil
+	}
+
+	if total > math.MaxInt64 {
+		return 0, &NumberRangeError{Value: text, Target: "time.Duration", Reason: NumberOverflow}
+	}
+
+	return time.Duration(total), nil
+}
+
+// the units used by `FormatDuration`, largest first
+var durationFormatUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"µs", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+//
+// Format the duration using days, hours, minutes, seconds and
+// sub-second units, like `3d4h` or `1m30s`, leaving out units
+// that are zero. The `precision` is the largest number of units
+// written, with smaller units truncated; zero or less writes all
+// of them. The result can be read back with `ParseDuration`.
+//
+func FormatDuration(duration time.Duration, precision int) string {
+	if duration == 0 {
+		return "0s"
+	}
+
+	var builder strings.Builder
+	remaining := uint64(duration)
+	if duration < 0 {
+		builder.WriteByte('-')
+		remaining = uint64(-duration)
+	}
+
+	written := 0
+	for _, unit := range durationFormatUnits {
+		count := remaining / uint64(unit.size)
+		if count == 0 {
+			continue
+		}
+
+		builder.WriteString(strconv.FormatUint(count, 10))
+		builder.WriteString(unit.name)
+		remaining -= count * uint64(unit.size)
+
+		written++
+		if written == precision {
+			break
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,161 @@
+/**
+ * berry - Utility functions for Go.
+ *
+ * MIT License.
+ * Copyright (c) 2022, Sandeep Gupta.
+ * https://github.com/sangupta/berry
+ *
+ * Use of this source code is governed by a MIT style license
+ * that can be found in LICENSE file in the code repository:
+ */
+
+package berry
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBytes(t *testing.T) {
+	tests := map[string]uint64{
+		"512":       512,
+		"512B":      512,
+		"1kB":       1000,
+		"1K":        1000,
+		"200MB":     200000000,
+		"200 mb":    200000000,
+		"1KiB":      1024,
+		"1ki":       1024,
+		"1.5GiB":    1610612736,
+		" 2 TB ":    2000000000000,
+		"1,000 KB":  1000000,
+		"0.1kB":     100,
+		"1.3B":      1,
+		"15EiB":     15 << 60,
+		"1_024 MiB": 1024 << 20,
+	}
+
+	for text, expected := range tests {
+		size, err := ParseBytes(text)
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, size, text)
+	}
+
+	var rangeErr *NumberRangeError
+	_, err := ParseBytes("16EiB")
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberOverflow, rangeErr.Reason)
+
+	_, err = ParseBytes("16.5EiB")
+	assert.ErrorAs(t, err, &rangeErr)
+
+	_, err = ParseBytes("-1MB")
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNegative, rangeErr.Reason)
+
+	_, err = ParseBytes("-1.5MB")
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, NumberNegative, rangeErr.Reason)
+
+	for _, text := range []string{"", "MB", "1XB", "1.2.3MB", "abc"} {
+		_, err = ParseBytes(text)
+		assert.ErrorIs(t, err, strconv.ErrSyntax, text)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "0 B", FormatBytes(0, ByteUnitsSI, 2))
+	assert.Equal(t, "999 B", FormatBytes(999, ByteUnitsSI, 2))
+	assert.Equal(t, "1.00 kB", FormatBytes(1000, ByteUnitsSI, 2))
+	assert.Equal(t, "1000 B", FormatBytes(1000, ByteUnitsIEC, 2))
+	assert.Equal(t, "1.50 GiB", FormatBytes(1610612736, ByteUnitsIEC, 2))
+	assert.Equal(t, "1.6 GB", FormatBytes(1610612736, ByteUnitsSI, 1))
+	assert.Equal(t, "200 MB", FormatBytes(200000000, ByteUnitsSI, 0))
+	assert.Equal(t, "1.00 MB", FormatBytes(999999, ByteUnitsSI, 2))
+	assert.Equal(t, "16.00 EiB", FormatBytes(math.MaxUint64, ByteUnitsIEC, 2))
+	assert.Equal(t, "18.4 EB", FormatBytes(math.MaxUint64, ByteUnitsSI, 1))
+
+	// round trip
+	size, err := ParseBytes(FormatBytes(1536, ByteUnitsIEC, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1536), size)
+}
+
+func TestConvertToBytes(t *testing.T) {
+	size, err := ConvertToBytes("1.5GiB", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1610612736), size)
+
+	size, err = ConvertToBytes(4096, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4096), size)
+
+	size, err = ConvertToBytes(nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), size)
+
+	size, err = ConvertToBytes("lots", 10)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(10), size)
+
+	_, err = ConvertToBytes(-1, 0)
+	assert.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"0":            0,
+		"90s":          90 * time.Second,
+		"1.5h":         90 * time.Minute,
+		"3d4h":         76 * time.Hour,
+		"1w":           168 * time.Hour,
+		"-2m30s":       -150 * time.Second,
+		" +1h ":        time.Hour,
+		"1h2m3s4ms":    time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond,
+		"10us":         10 * time.Microsecond,
+		"10µs":         10 * time.Microsecond,
+		"5ns":          5,
+		".5d":          12 * time.Hour,
+		"2562047h":     2562047 * time.Hour,
+		"-106751d":     -106751 * 24 * time.Hour,
+		"1.000000001s": time.Second + 1,
+	}
+
+	for text, expected := range tests {
+		duration, err := ParseDuration(text)
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, duration, text)
+	}
+
+	for _, text := range []string{"", "-", "90", "1x", "h", "1..5h", "1h30", "."} {
+		_, err := ParseDuration(text)
+		assert.ErrorIs(t, err, strconv.ErrSyntax, text)
+	}
+
+	var rangeErr *NumberRangeError
+	_, err := ParseDuration("300000w")
+	assert.ErrorAs(t, err, &rangeErr)
+	_, err = ParseDuration("99999999999999999999s")
+	assert.ErrorAs(t, err, &rangeErr)
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "0s", FormatDuration(0, 0))
+	assert.Equal(t, "1m30s", FormatDuration(90*time.Second, 0))
+	assert.Equal(t, "3d4h", FormatDuration(76*time.Hour, 0))
+	assert.Equal(t, "1d2h3m4s5ms", FormatDuration(26*time.Hour+3*time.Minute+4*time.Second+5*time.Millisecond, 0))
+	assert.Equal(t, "1d2h", FormatDuration(26*time.Hour+3*time.Minute+4*time.Second, 2))
+	assert.Equal(t, "-1h", FormatDuration(-time.Hour, 1))
+	assert.Equal(t, "1µs500ns", FormatDuration(1500, 0))
+
+	// round trip
+	for _, duration := range []time.Duration{1, 90 * time.Second, 76*time.Hour + 7, math.MaxInt64, math.MinInt64} {
+		parsed, err := ParseDuration(FormatDuration(duration, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, duration, parsed)
+	}
+}